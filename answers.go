@@ -0,0 +1,158 @@
+package ddg_search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// InstantAnswer is the response of the DuckDuckGo Instant Answer API. Fields
+// the API leaves empty are empty; a query without any instant answer is not
+// an error.
+type InstantAnswer struct {
+	Heading          string                 `json:"heading"`
+	Type             string                 `json:"type"` // A article, D disambiguation, C category, N name, E exclusive
+	Abstract         string                 `json:"abstract"`
+	AbstractSource   string                 `json:"abstract_source"`
+	AbstractURL      string                 `json:"abstract_url"`
+	Image            string                 `json:"image"`
+	Answer           string                 `json:"answer"`
+	AnswerType       string                 `json:"answer_type"`
+	Definition       string                 `json:"definition"`
+	DefinitionSource string                 `json:"definition_source"`
+	DefinitionURL    string                 `json:"definition_url"`
+	Entity           string                 `json:"entity"`
+	Infobox          []InfoboxEntry         `json:"infobox"`
+	RelatedTopics    []RelatedTopic         `json:"related_topics"`
+	Results          []RelatedTopic         `json:"results"`
+	Raw              map[string]interface{} `json:"raw,omitempty"`
+}
+
+// InfoboxEntry is one row of the infobox of an InstantAnswer
+type InfoboxEntry struct {
+	Label    string `json:"label"`
+	Value    string `json:"value"`
+	DataType string `json:"data_type"`
+}
+
+// RelatedTopic is a related topic or result of an InstantAnswer. Groups of
+// topics have a Name and their members in Topics.
+type RelatedTopic struct {
+	Text   string         `json:"text"`
+	URL    string         `json:"url"`
+	Icon   string         `json:"icon"`
+	Name   string         `json:"name"`
+	Topics []RelatedTopic `json:"topics"`
+}
+
+// Answers queries the DuckDuckGo Instant Answer API
+func (d *DDGS) Answers(query string) (*InstantAnswer, error) {
+	return d.AnswersContext(context.Background(), query)
+}
+
+// AnswersContext is like Answers but uses ctx for the rate limit wait and
+// the request
+func (d *DDGS) AnswersContext(ctx context.Context, query string) (*InstantAnswer, error) {
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is mandatory", ErrInvalidParams)
+	}
+	params := url.Values{
+		"q":           {query},
+		"format":      {"json"},
+		"no_html":     {"1"},
+		"no_redirect": {"1"},
+	}
+	var raw map[string]interface{}
+	if err := d.getJSON(ctx, EndpointAnswers, params, d.profile(d.pickProfile()), &raw); err != nil {
+		return nil, err
+	}
+	return newInstantAnswer(raw), nil
+}
+
+// newInstantAnswer converts an Instant Answer API response
+func newInstantAnswer(item map[string]interface{}) *InstantAnswer {
+	answer := &InstantAnswer{
+		Heading:          stringField(item, "Heading"),
+		Type:             stringField(item, "Type"),
+		Abstract:         stringField(item, "AbstractText"),
+		AbstractSource:   stringField(item, "AbstractSource"),
+		AbstractURL:      stringField(item, "AbstractURL"),
+		Image:            answerURL(stringField(item, "Image")),
+		Answer:           anyField(item, "Answer"),
+		AnswerType:       stringField(item, "AnswerType"),
+		Definition:       stringField(item, "Definition"),
+		DefinitionSource: stringField(item, "DefinitionSource"),
+		DefinitionURL:    stringField(item, "DefinitionURL"),
+		Entity:           stringField(item, "Entity"),
+		RelatedTopics:    newRelatedTopics(item["RelatedTopics"]),
+		Results:          newRelatedTopics(item["Results"]),
+		Raw:              item,
+	}
+	if answer.Abstract == "" {
+		answer.Abstract = stringField(item, "Abstract")
+	}
+
+	// Infobox is an empty string when there is none
+	if infobox, ok := item["Infobox"].(map[string]interface{}); ok {
+		content, _ := infobox["content"].([]interface{})
+		for _, c := range content {
+			entry, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			answer.Infobox = append(answer.Infobox, InfoboxEntry{
+				Label:    stringField(entry, "label"),
+				Value:    anyField(entry, "value"),
+				DataType: stringField(entry, "data_type"),
+			})
+		}
+	}
+	return answer
+}
+
+// newRelatedTopics converts a RelatedTopics or Results array
+func newRelatedTopics(v interface{}) []RelatedTopic {
+	items, _ := v.([]interface{})
+	var topics []RelatedTopic
+	for _, i := range items {
+		item, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		topic := RelatedTopic{
+			Text:   stringField(item, "Text"),
+			URL:    stringField(item, "FirstURL"),
+			Name:   stringField(item, "Name"),
+			Topics: newRelatedTopics(item["Topics"]),
+		}
+		if icon, ok := item["Icon"].(map[string]interface{}); ok {
+			topic.Icon = answerURL(stringField(icon, "URL"))
+		}
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// answerURL resolves the site-relative image paths of the Instant Answer API
+func answerURL(path string) string {
+	if strings.HasPrefix(path, "/") {
+		return "https://duckduckgo.com" + path
+	}
+	return path
+}
+
+// anyField returns item[key] as a string. Some answers and infobox values
+// are numbers or objects rather than strings.
+func anyField(item map[string]interface{}, key string) string {
+	switch v := item[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
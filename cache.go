@@ -0,0 +1,217 @@
+package ddg_search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// CacheKey identifies one page of search results
+type CacheKey struct {
+	Vertical   string `json:"vertical"`
+	Keywords   string `json:"keywords"`
+	Region     string `json:"region,omitempty"`
+	SafeSearch string `json:"safesearch,omitempty"`
+	Timelimit  string `json:"timelimit,omitempty"`
+	Filters    string `json:"filters,omitempty"` // backend for text, filter string otherwise
+	Page       int    `json:"page"`
+}
+
+// String returns a stable representation of the key
+func (k CacheKey) String() string {
+	return url.Values{
+		"vertical":   {k.Vertical},
+		"keywords":   {k.Keywords},
+		"region":     {k.Region},
+		"safesearch": {k.SafeSearch},
+		"timelimit":  {k.Timelimit},
+		"filters":    {k.Filters},
+		"page":       {strconv.Itoa(k.Page)},
+	}.Encode()
+}
+
+// Cache stores encoded result pages. Implementations must be safe for
+// concurrent use; the same cache may be shared by several DDGS instances.
+type Cache interface {
+	// Get returns the value stored for key, if any
+	Get(key CacheKey) ([]byte, bool)
+	// Set stores value for key
+	Set(key CacheKey, value []byte)
+}
+
+// WithCache caches every page of search results in cache. Use
+// ContextWithCachePolicy to bypass or refresh it for a single call and
+// ContextWithCacheStats to find out whether a call was served from it.
+func WithCache(cache Cache) func(*DDGS) {
+	return func(d *DDGS) {
+		d.cache = cache
+	}
+}
+
+// CachePolicy controls how a single call uses the cache
+type CachePolicy int
+
+const (
+	// CacheDefault serves pages from the cache and stores fetched pages
+	CacheDefault CachePolicy = iota
+	// CacheBypass neither reads nor writes the cache
+	CacheBypass
+	// CacheRefresh always fetches pages and stores them in the cache
+	CacheRefresh
+)
+
+// CacheStats counts the cache hits and misses of the calls made with a
+// context from ContextWithCacheStats. It must not be shared by concurrent
+// calls.
+type CacheStats struct {
+	Hits   int
+	Misses int
+}
+
+type cachePolicyKey struct{}
+
+type cacheStatsKey struct{}
+
+// ContextWithCachePolicy returns a context that makes searches use policy
+func ContextWithCachePolicy(ctx context.Context, policy CachePolicy) context.Context {
+	return context.WithValue(ctx, cachePolicyKey{}, policy)
+}
+
+// ContextWithCacheStats returns a context that makes searches record their
+// cache hits and misses in stats
+func ContextWithCacheStats(ctx context.Context, stats *CacheStats) context.Context {
+	return context.WithValue(ctx, cacheStatsKey{}, stats)
+}
+
+// cachedPageData is the encoded form of a page stored in a Cache
+type cachedPageData[T any] struct {
+	Results []T     `json:"results"`
+	Next    *Cursor `json:"next"`
+}
+
+// cachedPage serves the page c points at from the cache of d, or fetches
+// it and stores the result
+func cachedPage[T any](ctx context.Context, d *DDGS, c *Cursor, fetch pageFunc[T]) ([]T, *Cursor, error) {
+	policy, _ := ctx.Value(cachePolicyKey{}).(CachePolicy)
+	if d.cache == nil || c.key.Vertical == "" || policy == CacheBypass {
+		return fetch(ctx, c)
+	}
+	stats, _ := ctx.Value(cacheStatsKey{}).(*CacheStats)
+	key := c.key
+	key.Page = c.page
+
+	if policy != CacheRefresh {
+		if data, ok := d.cache.Get(key); ok {
+			var page cachedPageData[T]
+			if err := json.Unmarshal(data, &page); err == nil {
+				if stats != nil {
+					stats.Hits++
+				}
+				return page.Results, page.Next, nil
+			}
+		}
+	}
+	if stats != nil {
+		stats.Misses++
+	}
+
+	results, next, err := fetch(ctx, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data, err := json.Marshal(cachedPageData[T]{Results: results, Next: next}); err == nil {
+		d.cache.Set(key, data)
+	}
+	return results, next, nil
+}
+
+// LRUCache is an in-memory Cache that keeps the most recently used pages
+type LRUCache struct {
+	pages *lru[CacheKey, []byte]
+}
+
+// NewLRUCache returns a cache holding up to size pages for ttl each. A ttl
+// <= 0 keeps pages until they are evicted.
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{pages: newLRU[CacheKey, []byte](size, ttl)}
+}
+
+// Get implements Cache
+func (c *LRUCache) Get(key CacheKey) ([]byte, bool) {
+	return c.pages.get(key)
+}
+
+// Set implements Cache
+func (c *LRUCache) Set(key CacheKey, value []byte) {
+	c.pages.put(key, value)
+}
+
+// Len returns the number of cached pages
+func (c *LRUCache) Len() int {
+	return c.pages.len()
+}
+
+// FileCache is a Cache that stores every page in its own file, so cached
+// results survive restarts and can be shared between processes
+type FileCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewFileCache returns a cache storing pages in dir, which is created if
+// needed. Pages expire ttl after they were written; a ttl <= 0 keeps them
+// forever.
+func NewFileCache(dir string, ttl time.Duration) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("%w: cache directory: %v", ErrInvalidParams, err)
+	}
+	return &FileCache{dir: dir, ttl: ttl}, nil
+}
+
+// path returns the file of key
+func (c *FileCache) path(key CacheKey) string {
+	sum := sha256.Sum256([]byte(key.String()))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get implements Cache. Unreadable and expired files are misses.
+func (c *FileCache) Get(key CacheKey) ([]byte, bool) {
+	path := c.path(key)
+	if c.ttl > 0 {
+		info, err := os.Stat(path)
+		if err != nil || time.Since(info.ModTime()) > c.ttl {
+			return nil, false
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set implements Cache. The file is replaced atomically so concurrent
+// readers never see a partial page; write errors are ignored.
+func (c *FileCache) Set(key CacheKey, value []byte) {
+	tmp, err := os.CreateTemp(c.dir, "page-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,260 @@
+package ddgtest
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+)
+
+// SOCKS5Server is a minimal SOCKS5 proxy (RFC 1928) supporting the CONNECT
+// command and optional username/password authentication (RFC 1929)
+type SOCKS5Server struct {
+	listener net.Listener
+	username string
+	password string
+
+	mu          sync.Mutex
+	connections int
+	wg          sync.WaitGroup
+}
+
+// NewSOCKS5Server starts a SOCKS5 proxy on a local port. When username is
+// not empty, clients must authenticate with username and password.
+func NewSOCKS5Server(username, password string) *SOCKS5Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("ddgtest: failed to listen: " + err.Error())
+	}
+	s := &SOCKS5Server{listener: listener, username: username, password: password}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Addr returns the host:port the proxy listens on
+func (s *SOCKS5Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Connections returns how many CONNECT requests were served
+func (s *SOCKS5Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// Close stops the proxy
+func (s *SOCKS5Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *SOCKS5Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *SOCKS5Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	target, err := s.handshake(conn)
+	if err != nil {
+		return
+	}
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		// general SOCKS server failure
+		conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+
+	s.mu.Lock()
+	s.connections++
+	s.mu.Unlock()
+
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+// handshake negotiates authentication and returns the CONNECT target
+func (s *SOCKS5Server) handshake(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil || header[0] != 5 {
+		return "", errors.New("not a SOCKS5 client")
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", err
+	}
+
+	method := byte(0x00)
+	if s.username != "" {
+		method = 0x02
+	}
+	offered := false
+	for _, m := range methods {
+		if m == method {
+			offered = true
+		}
+	}
+	if !offered {
+		conn.Write([]byte{5, 0xff})
+		return "", errors.New("no acceptable authentication method")
+	}
+	if _, err := conn.Write([]byte{5, method}); err != nil {
+		return "", err
+	}
+
+	if method == 0x02 {
+		username, password, err := readCredentials(conn)
+		if err != nil {
+			return "", err
+		}
+		if username != s.username || password != s.password {
+			conn.Write([]byte{1, 1})
+			return "", errors.New("invalid credentials")
+		}
+		if _, err := conn.Write([]byte{1, 0}); err != nil {
+			return "", err
+		}
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", err
+	}
+	if request[1] != 1 {
+		// command not supported
+		conn.Write([]byte{5, 7, 0, 1, 0, 0, 0, 0, 0, 0})
+		return "", errors.New("unsupported command")
+	}
+
+	var host string
+	switch request[3] {
+	case 1:
+		addr := make([]byte, net.IPv4len)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = net.IP(addr).String()
+	case 3:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+		addr := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = string(addr)
+	case 4:
+		addr := make([]byte, net.IPv6len)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = net.IP(addr).String()
+	default:
+		return "", errors.New("unsupported address type")
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// readCredentials reads an RFC 1929 username/password request
+func readCredentials(conn net.Conn) (string, string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil || header[0] != 1 {
+		return "", "", errors.New("invalid authentication request")
+	}
+	username := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, username); err != nil {
+		return "", "", err
+	}
+	length := make([]byte, 1)
+	if _, err := io.ReadFull(conn, length); err != nil {
+		return "", "", err
+	}
+	password := make([]byte, length[0])
+	if _, err := io.ReadFull(conn, password); err != nil {
+		return "", "", err
+	}
+	return string(username), string(password), nil
+}
+
+// HTTPProxy is a forward HTTP proxy for plain http:// targets, optionally
+// requiring Basic Proxy-Authorization
+type HTTPProxy struct {
+	*httptest.Server
+	username string
+	password string
+
+	mu       sync.Mutex
+	requests int
+}
+
+// NewHTTPProxy starts a forward HTTP proxy. When username is not empty,
+// requests without matching credentials are answered with 407.
+func NewHTTPProxy(username, password string) *HTTPProxy {
+	p := &HTTPProxy{username: username, password: password}
+	p.Server = httptest.NewServer(http.HandlerFunc(p.serveHTTP))
+	return p
+}
+
+// Requests returns how many requests were forwarded
+func (p *HTTPProxy) Requests() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests
+}
+
+func (p *HTTPProxy) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if p.username != "" {
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte(p.username+":"+p.password))
+		if r.Header.Get("Proxy-Authorization") != want {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "not a proxy request", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	p.requests++
+	p.mu.Unlock()
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Authorization")
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
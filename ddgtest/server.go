@@ -0,0 +1,346 @@
+// Package ddgtest provides a fake DuckDuckGo server that serves recorded
+// fixtures, so ddg_search can be tested without network access.
+//
+// The server understands the same endpoints as the real service (VQD page,
+// html, lite, i.js, news.js, v.js, local.js, translation.js, ac/ and
+// the Instant Answer API) and switches fixtures based on the pagination
+// parameters the client sends. A few magic queries trigger edge cases:
+// QueryNoResults, QueryRatelimit, QueryMalformed and QueryManyPages.
+package ddgtest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Patrick7241/ddg_search"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+const (
+	// VQD is the token embedded in the fake VQD page
+	VQD = "4-123456789012345678901234567890"
+
+	// QueryNoResults returns the "No results." pages and empty JSON results
+	QueryNoResults = "no results"
+	// QueryRatelimit makes every search endpoint answer 429
+	QueryRatelimit = "ratelimit"
+	// QueryMalformed makes the JSON endpoints return a truncated body
+	QueryMalformed = "malformed"
+	// QueryManyPages makes the JSON endpoints return ManyPagesCount
+	// generated pages with one result each
+	QueryManyPages = "many pages"
+
+	// ManyPagesCount is the number of pages served for QueryManyPages
+	ManyPagesCount = 12
+)
+
+// Endpoint paths served by the fake server
+const (
+	PathVQD         = "/"
+	PathHTML        = "/html"
+	PathLite        = "/lite/"
+	PathImages      = "/i.js"
+	PathNews        = "/news.js"
+	PathVideos      = "/v.js"
+	PathMaps        = "/local.js"
+	PathTranslate   = "/translation.js"
+	PathAnswers     = "/answers"
+	PathSuggestions = "/ac/"
+)
+
+// Server is a fake DuckDuckGo server backed by httptest.Server
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests map[string]int
+	headers  map[string][]http.Header
+	statuses map[string]int
+	failures map[string][]failure
+}
+
+// failure is a queued error response
+type failure struct {
+	status     int
+	retryAfter string
+}
+
+// NewServer starts a new fake DuckDuckGo server. Callers should Close it.
+func NewServer() *Server {
+	s := &Server{
+		requests: map[string]int{},
+		headers:  map[string][]http.Header{},
+		statuses: map[string]int{},
+		failures: map[string][]failure{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BaseURLs returns the endpoint URLs to pass to ddg_search.WithBaseURLs
+func (s *Server) BaseURLs() ddg_search.BaseURLs {
+	return ddg_search.BaseURLs{
+		VQD:         s.URL + PathVQD,
+		HTML:        s.URL + PathHTML,
+		Lite:        s.URL + PathLite,
+		Images:      s.URL + PathImages,
+		News:        s.URL + PathNews,
+		Videos:      s.URL + PathVideos,
+		Maps:        s.URL + PathMaps,
+		Translate:   s.URL + PathTranslate,
+		Answers:     s.URL + PathAnswers,
+		Suggestions: s.URL + PathSuggestions,
+	}
+}
+
+// SetStatus forces every request to path to be answered with status.
+// A status of 0 restores the normal behaviour.
+func (s *Server) SetStatus(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status == 0 {
+		delete(s.statuses, path)
+		return
+	}
+	s.statuses[path] = status
+}
+
+// FailNext answers the next n requests to path with status. A non-empty
+// retryAfter is sent as the Retry-After header.
+func (s *Server) FailNext(path string, n, status int, retryAfter string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures[path] = append(s.failures[path], failure{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Requests returns how many requests were made to path
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// Headers returns the request headers of every request made to path, in
+// the order the requests arrived
+func (s *Server) Headers(path string) []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	headers := make([]http.Header, len(s.headers[path]))
+	copy(headers, s.headers[path])
+	return headers
+}
+
+// TotalRequests returns how many requests were made to any path
+func (s *Server) TotalRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, n := range s.requests {
+		total += n
+	}
+	return total
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests[r.URL.Path]++
+	s.headers[r.URL.Path] = append(s.headers[r.URL.Path], r.Header.Clone())
+	status := s.statuses[r.URL.Path]
+	if queued := s.failures[r.URL.Path]; len(queued) > 0 {
+		status = queued[0].status
+		if queued[0].retryAfter != "" {
+			w.Header().Set("Retry-After", queued[0].retryAfter)
+		}
+		s.failures[r.URL.Path] = queued[1:]
+	}
+	s.mu.Unlock()
+
+	if status != 0 {
+		w.WriteHeader(status)
+		w.Write([]byte("<html><body>blocked</body></html>"))
+		return
+	}
+
+	query := r.Form.Get("q")
+	if query == QueryRatelimit && r.URL.Path != PathVQD {
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	switch r.URL.Path {
+	case PathVQD:
+		s.serveFixture(w, "vqd.html")
+	case PathHTML:
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		s.servePage(w, query, r.Form.Get("s"), "10", "html_page1.html", "html_page2.html", "html_noresults.html")
+	case PathLite:
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		s.servePage(w, query, r.Form.Get("s"), "10", "lite_page1.html", "lite_page2.html", "lite_noresults.html")
+	case PathImages:
+		s.serveJSON(w, r, "100", "images_page1.json", "images_page2.json")
+	case PathNews:
+		s.serveJSON(w, r, "30", "news_page1.json", "news_page2.json")
+	case PathVideos:
+		s.serveJSON(w, r, "60", "videos_page1.json", "videos_page2.json")
+	case PathMaps:
+		s.serveJSON(w, r, "20", "maps_page1.json", "maps_page2.json")
+	case PathTranslate:
+		s.serveTranslation(w, r)
+	case PathAnswers:
+		s.serveAnswers(w, r)
+	case PathSuggestions:
+		w.Header().Set("Content-Type", "application/x-javascript")
+		switch r.Form.Get("q") {
+		case QueryNoResults:
+			w.Write([]byte("[]"))
+		case QueryMalformed:
+			s.serveFixture(w, "malformed.json")
+		default:
+			s.serveFixture(w, "suggestions.json")
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveJSON serves a JSON endpoint after checking the VQD token
+func (s *Server) serveJSON(w http.ResponseWriter, r *http.Request, nextOffset, page1, page2 string) {
+	if r.Form.Get("vqd") != VQD {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Form.Get("q") {
+	case QueryMalformed:
+		s.serveFixture(w, "malformed.json")
+		return
+	case QueryManyPages:
+		s.serveGeneratedPage(w, r.URL.Path, r.Form.Get("s"))
+		return
+	}
+	s.servePage(w, r.Form.Get("q"), r.Form.Get("s"), nextOffset, page1, page2, "empty.json")
+}
+
+// servePage picks the fixture matching the requested offset
+func (s *Server) servePage(w http.ResponseWriter, query, offset, nextOffset, page1, page2, empty string) {
+	switch {
+	case query == QueryNoResults:
+		s.serveFixture(w, empty)
+	case offset == "" || offset == "0":
+		s.serveFixture(w, page1)
+	case offset == nextOffset:
+		s.serveFixture(w, page2)
+	default:
+		s.serveFixture(w, empty)
+	}
+}
+
+// serveGeneratedPage serves one page of QueryManyPages. Every page holds a
+// single item that is valid for i.js, news.js, v.js and local.js.
+func (s *Server) serveGeneratedPage(w http.ResponseWriter, path, offset string) {
+	page, _ := strconv.Atoi(offset)
+	if page < 0 || page >= ManyPagesCount {
+		s.serveFixture(w, "empty.json")
+		return
+	}
+	link := fmt.Sprintf("https://example.com/many/%d", page)
+	resp := map[string]interface{}{
+		"results": []map[string]interface{}{{
+			"title":   fmt.Sprintf("Result %d", page),
+			"name":    fmt.Sprintf("Result %d", page),
+			"image":   link + ".png",
+			"url":     link,
+			"content": link,
+			"date":    1700000000 + page,
+		}},
+	}
+	if page+1 < ManyPagesCount {
+		resp["next"] = fmt.Sprintf("%s?q=many+pages&s=%d", strings.TrimPrefix(path, "/"), page+1)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// serveTranslation serves translation.js. The text to translate is the
+// request body; the fake translation tags it with the target language and
+// reports "de" as the detected language unless a source language is given.
+// A body of QueryMalformed or QueryRatelimit triggers the edge cases.
+func (s *Server) serveTranslation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Form.Get("vqd") != VQD {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	text, err := io.ReadAll(r.Body)
+	if err != nil || len(text) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch string(text) {
+	case QueryMalformed:
+		s.serveFixture(w, "malformed.json")
+		return
+	case QueryRatelimit:
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	resp := map[string]interface{}{
+		"detected_language": nil,
+		"translated":        fmt.Sprintf("[%s] %s", r.Form.Get("to"), text),
+	}
+	if r.Form.Get("from") == "" {
+		resp["detected_language"] = "de"
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// serveAnswers serves the Instant Answer API, which needs no VQD token
+func (s *Server) serveAnswers(w http.ResponseWriter, r *http.Request) {
+	if r.Form.Get("format") != "json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-javascript")
+	switch r.Form.Get("q") {
+	case QueryNoResults:
+		s.serveFixture(w, "answers_empty.json")
+	case QueryMalformed:
+		s.serveFixture(w, "malformed.json")
+	default:
+		s.serveFixture(w, "answers.json")
+	}
+}
+
+func (s *Server) serveFixture(w http.ResponseWriter, name string) {
+	data, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
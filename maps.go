@@ -0,0 +1,241 @@
+package ddg_search
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+// DefaultMapsRadius is the radius in kilometres used by Maps when a
+// Location has coordinates but no Radius
+const DefaultMapsRadius = 1.0
+
+// Location restricts a Maps search to an area. Set either Place, a place
+// name such as "Berlin", or Latitude and Longitude with an optional Radius.
+// Coordinates take precedence over Place; the zero Location lets
+// DuckDuckGo pick the area.
+type Location struct {
+	Place     string
+	Latitude  float64
+	Longitude float64
+	Radius    float64 // kilometres around the coordinates
+}
+
+// hasCoordinates reports whether l is a point rather than a place name
+func (l Location) hasCoordinates() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
+// bbox returns the top left and bottom right corners of the square of
+// Radius kilometres around the coordinates of l
+func (l Location) bbox() (topLeft, bottomRight string) {
+	radius := l.Radius
+	if radius == 0 {
+		radius = DefaultMapsRadius
+	}
+	// One degree of latitude is about 111.32 km, one degree of longitude
+	// shrinks with the cosine of the latitude
+	dLat := radius / 111.32
+	dLon := 180.0
+	if cos := math.Cos(l.Latitude * math.Pi / 180); cos > 1e-6 {
+		dLon = math.Min(radius/(111.32*cos), 180)
+	}
+	format := func(lat, lon float64) string {
+		lat = math.Max(-90, math.Min(90, lat))
+		lon = math.Max(-180, math.Min(180, lon))
+		return strconv.FormatFloat(lat, 'f', 6, 64) + "," + strconv.FormatFloat(lon, 'f', 6, 64)
+	}
+	return format(l.Latitude+dLat, l.Longitude-dLon), format(l.Latitude-dLat, l.Longitude+dLon)
+}
+
+// validate reports an error for coordinates or a radius out of range
+func (l Location) validate() error {
+	switch {
+	case l.Latitude < -90 || l.Latitude > 90 || math.IsNaN(l.Latitude):
+		return fmt.Errorf("%w: latitude %v out of range", ErrInvalidParams, l.Latitude)
+	case l.Longitude < -180 || l.Longitude > 180 || math.IsNaN(l.Longitude):
+		return fmt.Errorf("%w: longitude %v out of range", ErrInvalidParams, l.Longitude)
+	case l.Radius < 0 || math.IsNaN(l.Radius) || math.IsInf(l.Radius, 0):
+		return fmt.Errorf("%w: invalid radius %v", ErrInvalidParams, l.Radius)
+	}
+	return nil
+}
+
+// Place is a single result returned by Maps
+type Place struct {
+	Name        string            `json:"name"`
+	Address     string            `json:"address"`
+	CountryCode string            `json:"country_code"`
+	Latitude    float64           `json:"latitude"`
+	Longitude   float64           `json:"longitude"`
+	Phone       string            `json:"phone"`
+	Hours       map[string]string `json:"hours"` // opening hours by day, e.g. "Mon"
+	Rating      float64           `json:"rating"`
+	Reviews     int               `json:"reviews"`
+	Website     string            `json:"website"`
+	Category    string            `json:"category"`
+	Description string            `json:"description"`
+	Image       string            `json:"image"`
+	Source      string            `json:"source"` // listing the place was taken from
+	// Raw holds the decoded JSON item as returned by DuckDuckGo
+	Raw map[string]interface{} `json:"raw,omitempty"`
+}
+
+// Maps searches DuckDuckGo's local results for businesses and places
+// matching keywords in location
+func (d *DDGS) Maps(keywords string, location Location, maxResults int) ([]Place, error) {
+	return d.MapsContext(context.Background(), keywords, location, maxResults)
+}
+
+// MapsContext is like Maps but uses ctx for the VQD lookup, the rate limit
+// wait and every page fetch
+func (d *DDGS) MapsContext(ctx context.Context, keywords string, location Location, maxResults int) ([]Place, error) {
+	c, err := d.newMapsCursor(keywords, location)
+	if err != nil {
+		return nil, err
+	}
+	maxPages := 5
+	if maxResults == 0 {
+		maxPages = 1
+	}
+	return collect(ctx, c, d.mapsPage, placeKey, maxResults, maxPages)
+}
+
+// MapsSeq returns an iterator over places. Pages are fetched lazily while
+// the caller ranges, without a page limit.
+func (d *DDGS) MapsSeq(ctx context.Context, keywords string, location Location) iter.Seq2[Place, error] {
+	c, err := d.newMapsCursor(keywords, location)
+	if err != nil {
+		return errSeq[Place](err)
+	}
+	return seq(ctx, c, d.mapsPage, placeKey)
+}
+
+// MapsPage fetches the first page of a maps search. The returned cursor
+// points at the next page and is nil once there are no more pages.
+func (d *DDGS) MapsPage(ctx context.Context, keywords string, location Location) ([]Place, *Cursor, error) {
+	c, err := d.newMapsCursor(keywords, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	return single(ctx, c, d.mapsPage, placeKey)
+}
+
+// ResumeMaps fetches the page c points at, as returned by MapsPage or a
+// previous ResumeMaps call, or restored with ParseCursor. Results are only
+// deduplicated within the page.
+func (d *DDGS) ResumeMaps(ctx context.Context, c *Cursor) ([]Place, *Cursor, error) {
+	if err := c.check(verticalMaps); err != nil {
+		return nil, nil, err
+	}
+	return single(ctx, c, d.mapsPage, placeKey)
+}
+
+func (d *DDGS) newMapsCursor(keywords string, location Location) (*Cursor, error) {
+	if keywords == "" {
+		return nil, fmt.Errorf("%w: keywords is mandatory", ErrInvalidParams)
+	}
+	if err := location.validate(); err != nil {
+		return nil, err
+	}
+
+	// Build query params
+	params := url.Values{}
+	params.Set("o", "json")
+	params.Set("tg", "maps_places")
+	params.Set("rt", "D")
+	params.Set("mkexp", "b")
+	params.Set("wiki_info", "1")
+	params.Set("is_requery", "1")
+
+	query := keywords
+	var filters string
+	switch {
+	case location.hasCoordinates():
+		topLeft, bottomRight := location.bbox()
+		params.Set("bbox_tl", topLeft)
+		params.Set("bbox_br", bottomRight)
+		params.Set("strict_bbox", "1")
+		filters = topLeft + ";" + bottomRight
+	case location.Place != "":
+		// local.js has no place parameter, the place is part of the query
+		query = keywords + " near " + location.Place
+	}
+	params.Set("q", query)
+
+	return &Cursor{
+		vertical: verticalMaps,
+		profile:  d.pickProfile(),
+		key: CacheKey{
+			Vertical: string(verticalMaps),
+			Keywords: query,
+			Filters:  filters,
+		},
+		params: params,
+	}, nil
+}
+
+// mapsPage returns one page of local.js results, from the cache if possible
+func (d *DDGS) mapsPage(ctx context.Context, c *Cursor) ([]Place, *Cursor, error) {
+	return cachedPage(ctx, d, c, d.fetchMapsPage)
+}
+
+// fetchMapsPage fetches one page of local.js results
+func (d *DDGS) fetchMapsPage(ctx context.Context, c *Cursor) ([]Place, *Cursor, error) {
+	items, next, err := d.jsonPage(ctx, EndpointMaps, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []Place
+	for _, item := range items {
+		if stringField(item, "name") == "" {
+			continue
+		}
+		results = append(results, newPlace(item))
+	}
+	return results, next, nil
+}
+
+func placeKey(p Place) string {
+	return p.Name + "\n" + p.Address
+}
+
+// newPlace converts a decoded local.js item into a Place
+func newPlace(item map[string]interface{}) Place {
+	place := Place{
+		Name:        stringField(item, "name"),
+		Address:     stringField(item, "address"),
+		CountryCode: stringField(item, "country_code"),
+		Phone:       stringField(item, "phone"),
+		Rating:      floatField(item, "rating"),
+		Reviews:     intField(item, "reviews"),
+		Website:     stringField(item, "website"),
+		Category:    stringField(item, "ddg_category"),
+		Image:       stringField(item, "image"),
+		Source:      stringField(item, "url"),
+		Raw:         item,
+	}
+	if coordinates, ok := item["coordinates"].(map[string]interface{}); ok {
+		place.Latitude = floatField(coordinates, "latitude")
+		place.Longitude = floatField(coordinates, "longitude")
+	}
+	// hours also holds flags such as is_open, only the days are kept
+	if hours, ok := item["hours"].(map[string]interface{}); ok {
+		for day, v := range hours {
+			if s, ok := v.(string); ok && s != "" {
+				if place.Hours == nil {
+					place.Hours = map[string]string{}
+				}
+				place.Hours[day] = s
+			}
+		}
+	}
+	if embed, ok := item["embed"].(map[string]interface{}); ok {
+		place.Description = stringField(embed, "description")
+	}
+	return place
+}
@@ -0,0 +1,203 @@
+package ddg_search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TextOptions configures TextWithOptions, TextSeqWithOptions and
+// TextPageWithOptions. Zero fields take their default value.
+type TextOptions struct {
+	Region     Region          // default RegionWorldwide
+	SafeSearch SafeSearchLevel // default SafeSearchModerate
+	Timelimit  Timelimit       // default TimelimitAll
+	Backend    Backend         // default BackendAuto
+	MaxResults int             // <= 0 means no limit; ignored by Seq and Page
+}
+
+// ImagesOptions configures ImagesWithOptions, ImagesSeqWithOptions and
+// ImagesPageWithOptions. Zero fields take their default value.
+type ImagesOptions struct {
+	Region     Region          // default RegionWorldwide
+	SafeSearch SafeSearchLevel // default SafeSearchModerate
+	Timelimit  Timelimit       // default TimelimitAll; TimeRange is not supported
+	Filters    ImageFilters    // default no filter
+	MaxResults int             // <= 0 means no limit; ignored by Seq and Page
+}
+
+// NewsOptions configures NewsWithOptions, NewsSeqWithOptions and
+// NewsPageWithOptions. Zero fields take their default value.
+type NewsOptions struct {
+	Region     Region          // default RegionWorldwide
+	SafeSearch SafeSearchLevel // default SafeSearchModerate
+	Timelimit  Timelimit       // default TimelimitAll
+	MaxResults int             // <= 0 means no limit; ignored by Seq and Page
+}
+
+// VideosOptions configures VideosWithOptions, VideosSeqWithOptions and
+// VideosPageWithOptions. Zero fields take their default value.
+type VideosOptions struct {
+	Region     Region          // default RegionWorldwide
+	SafeSearch SafeSearchLevel // default SafeSearchModerate
+	Timelimit  Timelimit       // default TimelimitAll; TimeRange is not supported
+	Resolution Resolution      // default ResolutionAll
+	Duration   VideoDuration   // default DurationAll
+	License    VideoLicense    // default LicenseAll
+	MaxResults int             // <= 0 means no limit; ignored by Seq and Page
+}
+
+// Validate reports every invalid field as ErrInvalidParams
+func (o TextOptions) Validate() error {
+	var p problems
+	p.region(o.Region)
+	p.safeSearch(o.SafeSearch)
+	p.timelimit(o.Timelimit, true)
+	switch o.Backend {
+	case "", BackendAuto, BackendHTML, BackendLite:
+	default:
+		p.addf("unsupported backend %q", o.Backend)
+	}
+	return p.err()
+}
+
+// Validate reports every invalid field as ErrInvalidParams
+func (o ImagesOptions) Validate() error {
+	var p problems
+	p.region(o.Region)
+	p.safeSearch(o.SafeSearch)
+	p.timelimit(o.Timelimit, false)
+	oneOf(&p, "image size", string(o.Filters.Size),
+		ImageSizeSmall, ImageSizeMedium, ImageSizeLarge, ImageSizeWallpaper)
+	oneOf(&p, "image color", string(o.Filters.Color),
+		ImageColorColor, ImageColorMonochrome, ImageColorRed, ImageColorOrange, ImageColorYellow,
+		ImageColorGreen, ImageColorBlue, ImageColorPurple, ImageColorPink, ImageColorBrown,
+		ImageColorBlack, ImageColorGray, ImageColorTeal, ImageColorWhite)
+	oneOf(&p, "image type", string(o.Filters.Type),
+		ImageTypePhoto, ImageTypeClipart, ImageTypeGIF, ImageTypeTransparent, ImageTypeLine)
+	oneOf(&p, "image layout", string(o.Filters.Layout),
+		ImageLayoutSquare, ImageLayoutTall, ImageLayoutWide)
+	oneOf(&p, "image license", string(o.Filters.License),
+		ImageLicenseCreativeCommons, ImageLicensePublic, ImageLicenseShare,
+		ImageLicenseShareCommercially, ImageLicenseModify, ImageLicenseModifyCommercially)
+	return p.err()
+}
+
+// Validate reports every invalid field as ErrInvalidParams
+func (o NewsOptions) Validate() error {
+	var p problems
+	p.region(o.Region)
+	p.safeSearch(o.SafeSearch)
+	p.timelimit(o.Timelimit, true)
+	return p.err()
+}
+
+// Validate reports every invalid field as ErrInvalidParams
+func (o VideosOptions) Validate() error {
+	var p problems
+	p.region(o.Region)
+	p.safeSearch(o.SafeSearch)
+	p.timelimit(o.Timelimit, false)
+	oneOf(&p, "resolution", string(o.Resolution), ResolutionHigh, ResolutionStandard)
+	oneOf(&p, "duration", string(o.Duration), DurationShort, DurationMedium, DurationLong)
+	oneOf(&p, "license", string(o.License), LicenseCreativeCommon, LicenseYouTube)
+	return p.err()
+}
+
+// withDefaults returns o with its zero fields set to their default value
+func (o TextOptions) withDefaults() TextOptions {
+	o.Region, o.SafeSearch = defaultRegion(o.Region), defaultSafeSearch(o.SafeSearch)
+	if o.Backend == "" {
+		o.Backend = BackendAuto
+	}
+	return o
+}
+
+// withDefaults returns o with its zero fields set to their default value
+func (o ImagesOptions) withDefaults() ImagesOptions {
+	o.Region, o.SafeSearch = defaultRegion(o.Region), defaultSafeSearch(o.SafeSearch)
+	return o
+}
+
+// withDefaults returns o with its zero fields set to their default value
+func (o NewsOptions) withDefaults() NewsOptions {
+	o.Region, o.SafeSearch = defaultRegion(o.Region), defaultSafeSearch(o.SafeSearch)
+	return o
+}
+
+// withDefaults returns o with its zero fields set to their default value
+func (o VideosOptions) withDefaults() VideosOptions {
+	o.Region, o.SafeSearch = defaultRegion(o.Region), defaultSafeSearch(o.SafeSearch)
+	return o
+}
+
+func defaultRegion(region Region) Region {
+	if region == "" {
+		return RegionWorldwide
+	}
+	return region
+}
+
+func defaultSafeSearch(safesearch SafeSearchLevel) SafeSearchLevel {
+	if safesearch == "" {
+		return SafeSearchModerate
+	}
+	return safesearch
+}
+
+// problems collects the invalid fields of an options struct
+type problems []string
+
+func (p *problems) addf(format string, args ...interface{}) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+func (p *problems) region(region Region) {
+	if region != "" && !region.Valid() {
+		p.addf("unsupported region %q, see Regions", region)
+	}
+}
+
+func (p *problems) safeSearch(safesearch SafeSearchLevel) {
+	oneOf(p, "safesearch", string(safesearch), SafeSearchOn, SafeSearchModerate, SafeSearchOff)
+}
+
+// timelimit records an unknown time limit, a malformed or reversed range,
+// or a range for a vertical without support for ranges
+func (p *problems) timelimit(timelimit Timelimit, ranges bool) {
+	if !strings.Contains(string(timelimit), "..") {
+		oneOf(p, "timelimit", string(timelimit), TimelimitDay, TimelimitWeek, TimelimitMonth, TimelimitYear)
+		return
+	}
+	from, to, ok := timelimit.Range()
+	switch {
+	case !ok:
+		p.addf("malformed time range %q, want YYYY-MM-DD..YYYY-MM-DD", timelimit)
+	case to.Before(from):
+		p.addf("time range %q ends before it starts", timelimit)
+	case !ranges:
+		p.addf("time range %q is only supported by text and news", timelimit)
+	}
+}
+
+// oneOf records a problem unless value is empty or one of valid
+func oneOf[T ~string](p *problems, name, value string, valid ...T) {
+	if value == "" {
+		return
+	}
+	names := make([]string, len(valid))
+	for i, v := range valid {
+		if string(v) == value {
+			return
+		}
+		names[i] = string(v)
+	}
+	p.addf("unsupported %s %q, want one of %s", name, value, strings.Join(names, ", "))
+}
+
+// err returns nil or an ErrInvalidParams listing every problem
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidParams, strings.Join(p, "; "))
+}
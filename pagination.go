@@ -0,0 +1,237 @@
+package ddg_search
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+)
+
+// vertical identifies the kind of search a cursor belongs to
+type vertical string
+
+const (
+	verticalText   vertical = "text"
+	verticalImages vertical = "images"
+	verticalNews   vertical = "news"
+	verticalVideos vertical = "videos"
+	verticalMaps   vertical = "maps"
+)
+
+// Cursor marks a position in a paginated search. It holds the VQD token,
+// the offset and form fields of the next page and, for text searches, the
+// backend in use. A Cursor is opaque but can be serialized with MarshalText
+// or String and restored with ParseCursor, so a search can be resumed later,
+// even from another process.
+type Cursor struct {
+	vertical vertical
+	backend  Backend    // text only; BackendAuto until the first page picked one
+	profile  string     // name of the BrowserProfile of the search session
+	key      CacheKey   // cache key of the search, without the page
+	params   url.Values // query or form values of the next request
+	page     int        // number of pages fetched before this one
+}
+
+// cursorVersion is bumped whenever the serialized form changes
+const cursorVersion = 1
+
+// cursorJSON is the serialized form of a Cursor
+type cursorJSON struct {
+	Version  int        `json:"v"`
+	Vertical vertical   `json:"vertical"`
+	Backend  Backend    `json:"backend,omitempty"`
+	Profile  string     `json:"profile,omitempty"`
+	Key      *CacheKey  `json:"key,omitempty"`
+	Params   url.Values `json:"params"`
+	Page     int        `json:"page"`
+}
+
+// ParseCursor restores a cursor serialized with String or MarshalText
+func ParseCursor(s string) (*Cursor, error) {
+	c := &Cursor{}
+	if err := c.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Page returns the zero-based index of the page the cursor points at
+func (c *Cursor) Page() int {
+	return c.page
+}
+
+// String returns the serialized cursor
+func (c *Cursor) String() string {
+	text, _ := c.MarshalText()
+	return string(text)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (c *Cursor) MarshalText() ([]byte, error) {
+	var key *CacheKey
+	if c.key.Vertical != "" {
+		key = &c.key
+	}
+	data, err := json.Marshal(cursorJSON{
+		Version:  cursorVersion,
+		Vertical: c.vertical,
+		Backend:  c.backend,
+		Profile:  c.profile,
+		Key:      key,
+		Params:   c.params,
+		Page:     c.page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	text := make([]byte, base64.RawURLEncoding.EncodedLen(len(data)))
+	base64.RawURLEncoding.Encode(text, data)
+	return text, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (c *Cursor) UnmarshalText(text []byte) error {
+	data := make([]byte, base64.RawURLEncoding.DecodedLen(len(text)))
+	n, err := base64.RawURLEncoding.Decode(data, text)
+	if err != nil {
+		return fmt.Errorf("%w: malformed cursor: %v", ErrInvalidParams, err)
+	}
+	var cj cursorJSON
+	if err := json.Unmarshal(data[:n], &cj); err != nil {
+		return fmt.Errorf("%w: malformed cursor: %v", ErrInvalidParams, err)
+	}
+	if cj.Version != cursorVersion {
+		return fmt.Errorf("%w: unsupported cursor version %d", ErrInvalidParams, cj.Version)
+	}
+	switch cj.Vertical {
+	case verticalText, verticalImages, verticalNews, verticalVideos, verticalMaps:
+	default:
+		return fmt.Errorf("%w: unknown cursor vertical %q", ErrInvalidParams, cj.Vertical)
+	}
+	*c = Cursor{
+		vertical: cj.Vertical,
+		backend:  cj.Backend,
+		profile:  cj.Profile,
+		params:   cj.Params,
+		page:     cj.Page,
+	}
+	if cj.Key != nil {
+		c.key = *cj.Key
+	}
+	return nil
+}
+
+// check reports an error unless c is a cursor of the given vertical
+func (c *Cursor) check(v vertical) error {
+	if c == nil {
+		return fmt.Errorf("%w: nil cursor", ErrInvalidParams)
+	}
+	if c.vertical != v {
+		return fmt.Errorf("%w: cursor belongs to a %s search, not %s", ErrInvalidParams, c.vertical, v)
+	}
+	return nil
+}
+
+// next returns a copy of c pointing at the following page
+func (c *Cursor) next(params url.Values) *Cursor {
+	return &Cursor{
+		vertical: c.vertical,
+		backend:  c.backend,
+		profile:  c.profile,
+		key:      c.key,
+		params:   params,
+		page:     c.page + 1,
+	}
+}
+
+// pageFunc fetches the page c points at and returns the cursor of the
+// following page, or nil when there are no more pages
+type pageFunc[T any] func(ctx context.Context, c *Cursor) ([]T, *Cursor, error)
+
+// collect gathers up to maxResults deduplicated results from at most
+// maxPages pages. A maxResults <= 0 means no limit on the number of results.
+func collect[T any](ctx context.Context, c *Cursor, fetch pageFunc[T], key func(T) string, maxResults, maxPages int) ([]T, error) {
+	var results []T
+	seen := map[string]struct{}{}
+
+	for page := 0; c != nil && page < maxPages; page++ {
+		items, next, err := fetch(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			k := key(item)
+			if _, exists := seen[k]; exists {
+				continue
+			}
+			seen[k] = struct{}{}
+
+			results = append(results, item)
+
+			if maxResults > 0 && len(results) >= maxResults {
+				return results, nil
+			}
+		}
+		c = next
+	}
+
+	return results, nil
+}
+
+// seq lazily walks every page starting at c and yields deduplicated results.
+// The next page is only requested once the consumer has ranged over the
+// current one, and an error ends the sequence after being yielded.
+func seq[T any](ctx context.Context, c *Cursor, fetch pageFunc[T], key func(T) string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		seen := map[string]struct{}{}
+		for cur := c; cur != nil; {
+			items, next, err := fetch(ctx, cur)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				k := key(item)
+				if _, exists := seen[k]; exists {
+					continue
+				}
+				seen[k] = struct{}{}
+
+				if !yield(item, nil) {
+					return
+				}
+			}
+			cur = next
+		}
+	}
+}
+
+// single fetches the page c points at and drops duplicates within it
+func single[T any](ctx context.Context, c *Cursor, fetch pageFunc[T], key func(T) string) ([]T, *Cursor, error) {
+	items, next, err := fetch(ctx, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	var results []T
+	seen := map[string]struct{}{}
+	for _, item := range items {
+		k := key(item)
+		if _, exists := seen[k]; exists {
+			continue
+		}
+		seen[k] = struct{}{}
+		results = append(results, item)
+	}
+	return results, next, nil
+}
+
+// errSeq returns a sequence that only yields err
+func errSeq[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, err)
+	}
+}
@@ -0,0 +1,156 @@
+package ddg_search
+
+import (
+	"math/rand"
+	"net/http"
+)
+
+// BrowserProfile is a consistent set of browser headers. A search session,
+// i.e. the VQD lookup and every page of one search, uses a single profile so
+// DuckDuckGo sees the same browser throughout.
+type BrowserProfile struct {
+	// Name identifies the profile and must be unique; it is stored in
+	// cursors so resumed searches keep their profile
+	Name           string
+	UserAgent      string
+	Accept         string // Accept header of page navigations
+	AcceptLanguage string
+	// SecChUA, SecChUAMobile and SecChUAPlatform are the client hints sent
+	// by Chromium based browsers; leave them empty for other browsers
+	SecChUA         string
+	SecChUAMobile   string
+	SecChUAPlatform string
+}
+
+// Built-in browser profiles
+var (
+	ProfileChromeWindows = BrowserProfile{
+		Name:            "chrome-windows",
+		UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36",
+		Accept:          "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8",
+		AcceptLanguage:  "en-US,en;q=0.9",
+		SecChUA:         `"Chromium";v="140", "Not=A?Brand";v="24", "Google Chrome";v="140"`,
+		SecChUAMobile:   "?0",
+		SecChUAPlatform: `"Windows"`,
+	}
+	ProfileChromeMac = BrowserProfile{
+		Name:            "chrome-mac",
+		UserAgent:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36",
+		Accept:          "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8",
+		AcceptLanguage:  "en-US,en;q=0.9",
+		SecChUA:         `"Chromium";v="140", "Not=A?Brand";v="24", "Google Chrome";v="140"`,
+		SecChUAMobile:   "?0",
+		SecChUAPlatform: `"macOS"`,
+	}
+	ProfileEdgeWindows = BrowserProfile{
+		Name:            "edge-windows",
+		UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36 Edg/140.0.0.0",
+		Accept:          "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8",
+		AcceptLanguage:  "en-US,en;q=0.9",
+		SecChUA:         `"Chromium";v="140", "Not=A?Brand";v="24", "Microsoft Edge";v="140"`,
+		SecChUAMobile:   "?0",
+		SecChUAPlatform: `"Windows"`,
+	}
+	ProfileFirefoxWindows = BrowserProfile{
+		Name:           "firefox-windows",
+		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:143.0) Gecko/20100101 Firefox/143.0",
+		Accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		AcceptLanguage: "en-US,en;q=0.5",
+	}
+	ProfileFirefoxLinux = BrowserProfile{
+		Name:           "firefox-linux",
+		UserAgent:      "Mozilla/5.0 (X11; Linux x86_64; rv:143.0) Gecko/20100101 Firefox/143.0",
+		Accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		AcceptLanguage: "en-US,en;q=0.5",
+	}
+	ProfileSafariMac = BrowserProfile{
+		Name:           "safari-mac",
+		UserAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.6 Safari/605.1.15",
+		Accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		AcceptLanguage: "en-US,en;q=0.9",
+	}
+)
+
+// BrowserProfiles lists every built-in profile
+var BrowserProfiles = []BrowserProfile{
+	ProfileChromeWindows,
+	ProfileChromeMac,
+	ProfileEdgeWindows,
+	ProfileFirefoxWindows,
+	ProfileFirefoxLinux,
+	ProfileSafariMac,
+}
+
+// WithBrowserProfile makes every search use profile. The default is
+// ProfileChromeWindows.
+func WithBrowserProfile(profile BrowserProfile) func(*DDGS) {
+	return func(d *DDGS) {
+		d.profiles = []BrowserProfile{profile}
+	}
+}
+
+// WithBrowserProfileRotation picks a random profile out of profiles for
+// every search session. Without arguments it rotates over BrowserProfiles.
+func WithBrowserProfileRotation(profiles ...BrowserProfile) func(*DDGS) {
+	return func(d *DDGS) {
+		if len(profiles) == 0 {
+			profiles = BrowserProfiles
+		}
+		d.profiles = append([]BrowserProfile(nil), profiles...)
+	}
+}
+
+// fetchMode tells which kind of browser request is imitated
+type fetchMode int
+
+const (
+	// modeNavigate is a page load or form submission
+	modeNavigate fetchMode = iota
+	// modeCORS is a script fetching JSON from the page
+	modeCORS
+)
+
+// apply sets the headers the browser would send for a request of mode
+func (p BrowserProfile) apply(h http.Header, mode fetchMode) {
+	set := func(key, value string) {
+		if value != "" {
+			h.Set(key, value)
+		}
+	}
+	set("User-Agent", p.UserAgent)
+	set("Accept-Language", p.AcceptLanguage)
+	set("Sec-Ch-Ua", p.SecChUA)
+	set("Sec-Ch-Ua-Mobile", p.SecChUAMobile)
+	set("Sec-Ch-Ua-Platform", p.SecChUAPlatform)
+	set("Sec-Fetch-Site", "same-origin")
+
+	switch mode {
+	case modeNavigate:
+		set("Accept", p.Accept)
+		set("Upgrade-Insecure-Requests", "1")
+		set("Sec-Fetch-Dest", "document")
+		set("Sec-Fetch-Mode", "navigate")
+		set("Sec-Fetch-User", "?1")
+	case modeCORS:
+		set("Accept", "application/json, text/javascript, */*; q=0.01")
+		set("Sec-Fetch-Dest", "empty")
+		set("Sec-Fetch-Mode", "cors")
+	}
+}
+
+// pickProfile returns the name of the profile for a new search session
+func (d *DDGS) pickProfile() string {
+	return d.profiles[rand.Intn(len(d.profiles))].Name
+}
+
+// profile returns the profile called name. Cursors restored from another
+// process may name a profile this client does not use; they fall back to
+// the first one.
+func (d *DDGS) profile(name string) BrowserProfile {
+	for _, p := range d.profiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	return d.profiles[0]
+}
@@ -0,0 +1,153 @@
+package ddg_search
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// WithNoProxy sets the hosts that bypass the proxy, using NO_PROXY syntax:
+// a comma-separated list of host names (matching the host and all of its
+// subdomains, with or without a leading "."), IP addresses, CIDR ranges,
+// optional ":port" suffixes, or "*" for every host. When unset, the NO_PROXY
+// or no_proxy environment variable is used.
+func WithNoProxy(noProxy string) func(*DDGS) {
+	return func(d *DDGS) {
+		d.noProxy = &noProxy
+	}
+}
+
+// parseProxy parses a proxy address. The http, https, socks5 and socks5h
+// schemes are supported and credentials may be given as userinfo.
+// Addresses without a scheme, such as "127.0.0.1:7890", are HTTP proxies.
+func parseProxy(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid proxy: %v", ErrInvalidParams, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("%w: unsupported proxy scheme %q", ErrInvalidParams, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("%w: proxy %q has no host", ErrInvalidParams, u.Redacted())
+	}
+	return u, nil
+}
+
+// proxyFunc returns an http.Transport Proxy function that sends every
+// request through the pool proxy attached to its context, or proxyURL,
+// except for the hosts matched by bypass
+func proxyFunc(proxyURL *url.URL, bypass noProxyList) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass.match(req.URL) {
+			return nil, nil
+		}
+		if proxy := poolProxyFrom(req.Context()); proxy != nil {
+			return proxy.url, nil
+		}
+		return proxyURL, nil
+	}
+}
+
+// noProxyEnv returns the NO_PROXY environment variable
+func noProxyEnv() string {
+	if v := os.Getenv("NO_PROXY"); v != "" {
+		return v
+	}
+	return os.Getenv("no_proxy")
+}
+
+// noProxyList is a parsed NO_PROXY value
+type noProxyList struct {
+	all      bool
+	networks []*net.IPNet
+	ips      []noProxyIP
+	domains  []noProxyDomain
+}
+
+type noProxyIP struct {
+	ip   net.IP
+	port string
+}
+
+type noProxyDomain struct {
+	domain string // without leading "."
+	port   string
+}
+
+func parseNoProxy(value string) noProxyList {
+	var list noProxyList
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			list.all = true
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			list.networks = append(list.networks, network)
+			continue
+		}
+		host, port := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			host, port = h, p
+		}
+		if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+			list.ips = append(list.ips, noProxyIP{ip: ip, port: port})
+			continue
+		}
+		list.domains = append(list.domains, noProxyDomain{domain: strings.TrimPrefix(host, "."), port: port})
+	}
+	return list
+}
+
+// match reports whether requests to u bypass the proxy
+func (l noProxyList) match(u *url.URL) bool {
+	if l.all {
+		return true
+	}
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, network := range l.networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		for _, entry := range l.ips {
+			if entry.ip.Equal(ip) && (entry.port == "" || entry.port == port) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, entry := range l.domains {
+		if entry.port != "" && entry.port != port {
+			continue
+		}
+		if host == entry.domain || strings.HasSuffix(host, "."+entry.domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package ddg_search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ProxyStrategy selects the next proxy of a ProxyPool
+type ProxyStrategy int
+
+const (
+	// ProxyRoundRobin uses the proxies in turn
+	ProxyRoundRobin ProxyStrategy = iota
+	// ProxyRandom picks a random proxy for every request
+	ProxyRandom
+	// ProxyLeastRecentlyUsed picks the proxy that has been idle the longest
+	ProxyLeastRecentlyUsed
+)
+
+// ProxyStats describes the usage and health of a single proxy of a ProxyPool
+type ProxyStats struct {
+	// Proxy is the proxy URL with its password redacted
+	Proxy string
+	// Requests is the number of requests sent through the proxy
+	Requests int
+	// Successes is the number of requests answered with 200 OK
+	Successes int
+	// Failures is the number of requests that failed with ErrRatelimit,
+	// ErrTimeout or a connection error
+	Failures int
+	// ConsecutiveFailures is the number of failures since the last success
+	ConsecutiveFailures int
+	// Healthy is false while the proxy is cooling down
+	Healthy bool
+	// CooldownUntil is the end of the current cool down, if any
+	CooldownUntil time.Time
+	// LastUsed is when the proxy was last picked
+	LastUsed time.Time
+}
+
+// ProxyPool rotates requests over several proxies. A proxy that fails
+// maxFailures times in a row is marked unhealthy and skipped until its cool
+// down ends. ProxyPool is safe for concurrent use and may be shared by
+// several DDGS instances.
+type ProxyPool struct {
+	mu          sync.Mutex
+	strategy    ProxyStrategy
+	proxies     []*poolProxy
+	next        int
+	maxFailures int
+	cooldown    time.Duration
+}
+
+// poolProxy is a proxy of a ProxyPool and its statistics
+type poolProxy struct {
+	url   *url.URL
+	stats ProxyStats
+}
+
+// NewProxyPool returns a pool of the given proxies, which use the same
+// syntax as WithProxy. By default a proxy is cooled down for one minute
+// after three consecutive failures.
+func NewProxyPool(strategy ProxyStrategy, proxies ...string) (*ProxyPool, error) {
+	switch strategy {
+	case ProxyRoundRobin, ProxyRandom, ProxyLeastRecentlyUsed:
+	default:
+		return nil, fmt.Errorf("%w: unknown proxy strategy %d", ErrInvalidParams, strategy)
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("%w: proxy pool is empty", ErrInvalidParams)
+	}
+	pool := &ProxyPool{
+		strategy:    strategy,
+		maxFailures: 3,
+		cooldown:    time.Minute,
+	}
+	for _, proxy := range proxies {
+		u, err := parseProxy(proxy)
+		if err != nil {
+			return nil, err
+		}
+		pool.proxies = append(pool.proxies, &poolProxy{
+			url:   u,
+			stats: ProxyStats{Proxy: u.Redacted()},
+		})
+	}
+	return pool, nil
+}
+
+// SetHealth marks a proxy unhealthy after maxFailures consecutive failures
+// and skips it for cooldown. It returns p for chaining.
+func (p *ProxyPool) SetHealth(maxFailures int, cooldown time.Duration) *ProxyPool {
+	if maxFailures < 1 {
+		maxFailures = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxFailures = maxFailures
+	p.cooldown = cooldown
+	return p
+}
+
+// Stats returns the statistics of every proxy in the pool
+func (p *ProxyPool) Stats() []ProxyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	stats := make([]ProxyStats, len(p.proxies))
+	for i, proxy := range p.proxies {
+		stats[i] = proxy.stats
+		stats[i].Healthy = proxy.healthy(now)
+	}
+	return stats
+}
+
+// WithProxyPool rotates the requests of the DDGS client over the proxies of
+// pool. It takes precedence over WithProxy and DDGS_PROXY; WithNoProxy and
+// NO_PROXY still apply.
+func WithProxyPool(pool *ProxyPool) func(*DDGS) {
+	return func(d *DDGS) {
+		d.proxyPool = pool
+	}
+}
+
+func (pp *poolProxy) healthy(now time.Time) bool {
+	return !now.Before(pp.stats.CooldownUntil)
+}
+
+// pick selects the proxy for the next request. When every proxy is cooling
+// down, the one that recovers first is used rather than failing the search.
+func (p *ProxyPool) pick() *poolProxy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+
+	var healthy []*poolProxy
+	for _, proxy := range p.proxies {
+		if proxy.healthy(now) {
+			healthy = append(healthy, proxy)
+		}
+	}
+
+	var chosen *poolProxy
+	switch {
+	case len(healthy) == 0:
+		for _, proxy := range p.proxies {
+			if chosen == nil || proxy.stats.CooldownUntil.Before(chosen.stats.CooldownUntil) {
+				chosen = proxy
+			}
+		}
+	case p.strategy == ProxyRandom:
+		chosen = healthy[rand.Intn(len(healthy))]
+	case p.strategy == ProxyLeastRecentlyUsed:
+		for _, proxy := range healthy {
+			if chosen == nil || proxy.stats.LastUsed.Before(chosen.stats.LastUsed) {
+				chosen = proxy
+			}
+		}
+	default:
+		for range p.proxies {
+			proxy := p.proxies[p.next]
+			p.next = (p.next + 1) % len(p.proxies)
+			if proxy.healthy(now) {
+				chosen = proxy
+				break
+			}
+		}
+	}
+
+	chosen.stats.Requests++
+	chosen.stats.LastUsed = now
+	return chosen
+}
+
+// report records the outcome of a request sent through proxy. Errors that
+// say nothing about the proxy, such as a cancelled context or a 5xx status
+// from DuckDuckGo, are ignored.
+func (p *ProxyPool) report(proxy *poolProxy, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		proxy.stats.Successes++
+		proxy.stats.ConsecutiveFailures = 0
+		return
+	}
+	var statusErr *StatusError
+	failed := errors.Is(err, ErrRatelimit) || errors.Is(err, ErrTimeout) ||
+		(errors.Is(err, ErrSearch) && !errors.As(err, &statusErr))
+	if !failed {
+		return
+	}
+	proxy.stats.Failures++
+	proxy.stats.ConsecutiveFailures++
+	if proxy.stats.ConsecutiveFailures >= p.maxFailures {
+		proxy.stats.CooldownUntil = time.Now().Add(p.cooldown)
+	}
+}
+
+type poolProxyKey struct{}
+
+// withPoolProxy returns a context that routes the request through proxy
+func withPoolProxy(ctx context.Context, proxy *poolProxy) context.Context {
+	return context.WithValue(ctx, poolProxyKey{}, proxy)
+}
+
+// poolProxyFrom returns the pool proxy attached to ctx, if any
+func poolProxyFrom(ctx context.Context) *poolProxy {
+	proxy, _ := ctx.Value(poolProxyKey{}).(*poolProxy)
+	return proxy
+}
@@ -0,0 +1,410 @@
+package ddg_search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Query builds search keywords with DuckDuckGo search operators. The zero
+// value is an empty query; methods return q so calls can be chained:
+//
+//	q := NewQuery("generics").Phrase("type parameters").Site("go.dev").FileType("pdf")
+//	results, err := ddgs.Text(q.String(), ...)
+//
+// Invalid operators, such as a site: with spaces or a second filetype:, are
+// reported by Build.
+type Query struct {
+	terms        []string
+	phrases      []string
+	exclude      []string
+	or           [][]string
+	inTitle      []string
+	inURL        []string
+	site         string
+	excludeSites []string
+	fileType     string
+	bang         string
+	err          error // first invalid operator, reported by Build
+}
+
+// NewQuery returns a query matching the given keywords
+func NewQuery(terms ...string) *Query {
+	return (&Query{}).Terms(terms...)
+}
+
+// Terms adds plain keywords. Whitespace separates keywords. Operators are
+// not keywords: a lone OR or a word starting with "-", "!" or an operator
+// such as "site:" is an error, use the matching method or ParseQuery instead.
+func (q *Query) Terms(terms ...string) *Query {
+	for _, t := range terms {
+		for _, word := range strings.Fields(t) {
+			if strings.ContainsRune(word, '"') {
+				q.fail("keyword %q contains a quote", word)
+				continue
+			}
+			if isOperator(word) {
+				q.fail("keyword %q is an operator", word)
+				continue
+			}
+			q.terms = append(q.terms, word)
+		}
+	}
+	return q
+}
+
+// Phrase adds an exact phrase, rendered in quotes
+func (q *Query) Phrase(phrase string) *Query {
+	if phrase = q.value("phrase", phrase, true); phrase != "" {
+		q.phrases = append(q.phrases, phrase)
+	}
+	return q
+}
+
+// Exclude excludes results containing a keyword or phrase (-term)
+func (q *Query) Exclude(term string) *Query {
+	if term = q.value("exclusion", term, true); term != "" {
+		q.exclude = append(q.exclude, term)
+	}
+	return q
+}
+
+// Or adds a group of keywords or phrases of which at least one must match
+// (a OR b)
+func (q *Query) Or(alternatives ...string) *Query {
+	if len(alternatives) < 2 {
+		q.fail("an OR group needs at least two alternatives, got %d", len(alternatives))
+		return q
+	}
+	group := make([]string, 0, len(alternatives))
+	for _, alt := range alternatives {
+		if alt = q.value("OR alternative", alt, true); alt == "" {
+			return q
+		}
+		group = append(group, alt)
+	}
+	q.or = append(q.or, group)
+	return q
+}
+
+// InTitle requires a word or phrase in the page title (intitle:)
+func (q *Query) InTitle(text string) *Query {
+	if text = q.value("intitle:", text, true); text != "" {
+		q.inTitle = append(q.inTitle, text)
+	}
+	return q
+}
+
+// InURL requires a word in the page URL (inurl:)
+func (q *Query) InURL(text string) *Query {
+	if text = q.value("inurl:", text, false); text != "" {
+		q.inURL = append(q.inURL, text)
+	}
+	return q
+}
+
+// Site restricts results to a domain (site:). DuckDuckGo honours a single
+// site: operator, so calling Site twice is an error.
+func (q *Query) Site(domain string) *Query {
+	domain = q.value("site:", domain, false)
+	switch {
+	case domain == "":
+	case q.site != "" && q.site != domain:
+		q.fail("only one site: is supported, got %q and %q", q.site, domain)
+	default:
+		q.site = domain
+	}
+	return q
+}
+
+// ExcludeSite excludes results from a domain (-site:)
+func (q *Query) ExcludeSite(domain string) *Query {
+	if domain = q.value("-site:", domain, false); domain != "" {
+		q.excludeSites = append(q.excludeSites, domain)
+	}
+	return q
+}
+
+// FileType restricts results to a file extension such as "pdf"
+// (filetype:). DuckDuckGo honours a single filetype: operator.
+func (q *Query) FileType(ext string) *Query {
+	ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+	for _, r := range ext {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			q.fail("invalid filetype %q", ext)
+			return q
+		}
+	}
+	switch {
+	case ext == "":
+		q.fail("empty filetype")
+	case q.fileType != "" && q.fileType != ext:
+		q.fail("only one filetype: is supported, got %q and %q", q.fileType, ext)
+	default:
+		q.fileType = ext
+	}
+	return q
+}
+
+// Bang redirects the search to another site with a bang command such as
+// "w" or "!gh". A query has at most one bang.
+func (q *Query) Bang(bang string) *Query {
+	bang = q.value("bang", strings.TrimPrefix(strings.TrimSpace(bang), "!"), false)
+	switch {
+	case bang == "":
+	case q.bang != "" && q.bang != bang:
+		q.fail("only one bang is supported, got !%s and !%s", q.bang, bang)
+	default:
+		q.bang = bang
+	}
+	return q
+}
+
+// value trims an operator value and records an error if it is empty,
+// contains a quote or, unless spaces is set, contains whitespace
+func (q *Query) value(operator, v string, spaces bool) string {
+	v = strings.TrimSpace(v)
+	switch {
+	case v == "":
+		q.fail("empty %s", operator)
+	case strings.ContainsRune(v, '"'):
+		q.fail("%s %q contains a quote", operator, v)
+	case !spaces && strings.IndexFunc(v, unicode.IsSpace) >= 0:
+		q.fail("%s %q contains whitespace", operator, v)
+	default:
+		return v
+	}
+	return ""
+}
+
+// fail records the first invalid operator
+func (q *Query) fail(format string, args ...interface{}) {
+	if q.err == nil {
+		q.err = fmt.Errorf("%w: query: "+format, append([]interface{}{ErrInvalidParams}, args...)...)
+	}
+}
+
+// Build validates the query and returns the keywords to pass to Text,
+// Images, News or Videos
+func (q *Query) Build() (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	for _, domain := range q.excludeSites {
+		if strings.EqualFold(domain, q.site) {
+			return "", fmt.Errorf("%w: query: site %q is both required and excluded", ErrInvalidParams, domain)
+		}
+	}
+	s := q.String()
+	if s == "" {
+		return "", fmt.Errorf("%w: query: empty query", ErrInvalidParams)
+	}
+	return s, nil
+}
+
+// String renders the query with DuckDuckGo's operator syntax, without
+// validating it
+func (q *Query) String() string {
+	var parts []string
+	if q.bang != "" {
+		parts = append(parts, "!"+q.bang)
+	}
+	parts = append(parts, q.terms...)
+	for _, p := range q.phrases {
+		parts = append(parts, `"`+p+`"`)
+	}
+	for _, group := range q.or {
+		alternatives := make([]string, len(group))
+		for i, alt := range group {
+			alternatives[i] = quote(alt)
+		}
+		parts = append(parts, strings.Join(alternatives, " OR "))
+	}
+	for _, t := range q.inTitle {
+		parts = append(parts, "intitle:"+quote(t))
+	}
+	for _, u := range q.inURL {
+		parts = append(parts, "inurl:"+u)
+	}
+	for _, e := range q.exclude {
+		parts = append(parts, "-"+quote(e))
+	}
+	if q.site != "" {
+		parts = append(parts, "site:"+q.site)
+	}
+	for _, domain := range q.excludeSites {
+		parts = append(parts, "-site:"+domain)
+	}
+	if q.fileType != "" {
+		parts = append(parts, "filetype:"+q.fileType)
+	}
+	return strings.Join(parts, " ")
+}
+
+// quote quotes s if it would otherwise not read back as a single keyword:
+// several words, a lone OR or something that looks like an operator
+func quote(s string) string {
+	if s == "OR" || strings.IndexFunc(s, unicode.IsSpace) >= 0 ||
+		strings.HasPrefix(s, "-") || strings.HasPrefix(s, "!") || strings.Contains(s, ":") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// queryToken is a whitespace separated part of a query string. A quoted
+// token keeps the whitespace of its value and loses its quotes; prefix is
+// what came before the opening quote, e.g. "-" or "intitle:".
+type queryToken struct {
+	prefix string
+	text   string
+	quoted bool
+}
+
+// isOr reports whether tok is the OR operator
+func (tok queryToken) isOr() bool {
+	return !tok.quoted && tok.text == "OR"
+}
+
+// ParseQuery parses keywords written with DuckDuckGo's operator syntax,
+// e.g. `golang "type parameters" -java site:go.dev`, into a Query. Invalid
+// or unterminated operators return ErrInvalidParams.
+func ParseQuery(s string) (*Query, error) {
+	tokens, err := tokenizeQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	q := &Query{}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.isOr() {
+			return nil, fmt.Errorf("%w: query: OR without alternatives", ErrInvalidParams)
+		}
+		if i+1 < len(tokens) && tokens[i+1].isOr() {
+			// a OR b OR c
+			group := []string{tok.text}
+			for ; i+1 < len(tokens) && tokens[i+1].isOr(); i += 2 {
+				if !isAlternative(tokens[i]) || i+2 >= len(tokens) || !isAlternative(tokens[i+2]) {
+					return nil, fmt.Errorf("%w: query: invalid OR group", ErrInvalidParams)
+				}
+				group = append(group, tokens[i+2].text)
+			}
+			q.Or(group...)
+			continue
+		}
+		parseQueryToken(q, tok)
+	}
+	if _, err := q.Build(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// tokenizeQuery splits s at whitespace outside of quotes
+func tokenizeQuery(s string) ([]queryToken, error) {
+	var tokens []queryToken
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimLeftFunc(s, unicode.IsSpace) {
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end < 0 {
+			end = len(s)
+		}
+		quote := strings.IndexByte(s[:end], '"')
+		if quote < 0 {
+			tokens = append(tokens, queryToken{text: s[:end]})
+			s = s[end:]
+			continue
+		}
+		closing := strings.IndexByte(s[quote+1:], '"')
+		if closing < 0 {
+			return nil, fmt.Errorf("%w: query: unterminated quote", ErrInvalidParams)
+		}
+		closing += quote + 1
+		if closing+1 < len(s) && !unicode.IsSpace(rune(s[closing+1])) {
+			return nil, fmt.Errorf("%w: query: unexpected text after quote", ErrInvalidParams)
+		}
+		tokens = append(tokens, queryToken{prefix: s[:quote], text: s[quote+1 : closing], quoted: true})
+		s = s[closing+1:]
+	}
+	return tokens, nil
+}
+
+// isAlternative reports whether tok can be part of an OR group
+func isAlternative(tok queryToken) bool {
+	if tok.quoted {
+		return tok.prefix == ""
+	}
+	return !tok.isOr() && !strings.HasPrefix(tok.text, "-") && !strings.HasPrefix(tok.text, "!") && !strings.Contains(tok.text, ":")
+}
+
+// queryOperators are the operators ParseQuery understands
+var queryOperators = []string{"site:", "filetype:", "intitle:", "inurl:"}
+
+// splitOperator splits s into an optional "-", a known operator and the
+// rest, e.g. "-site:example.com" into "-site:" and "example.com"
+func splitOperator(s string) (operator, rest string) {
+	if strings.HasPrefix(s, "-") {
+		operator, s = "-", s[1:]
+	}
+	for _, op := range queryOperators {
+		if len(s) >= len(op) && strings.EqualFold(s[:len(op)], op) {
+			return operator + op, s[len(op):]
+		}
+	}
+	return operator, s
+}
+
+// isOperator reports whether word would not read back as a plain keyword:
+// OR, an exclusion, a bang or one of queryOperators. A lone "-" or "!" is a
+// keyword.
+func isOperator(word string) bool {
+	if word == "OR" || (len(word) > 1 && (word[0] == '-' || word[0] == '!')) {
+		return true
+	}
+	operator, _ := splitOperator(word)
+	return len(operator) > 1
+}
+
+// parseQueryToken adds a single token to q
+func parseQueryToken(q *Query, tok queryToken) {
+	var operator, value string
+	switch {
+	case tok.quoted:
+		var rest string
+		operator, rest = splitOperator(tok.prefix)
+		if rest != "" {
+			q.fail("unsupported operator %q", tok.prefix)
+			return
+		}
+		value = tok.text
+	case len(tok.text) > 1 && tok.text[0] == '!':
+		q.Bang(tok.text)
+		return
+	case tok.text == "-":
+		q.Terms(tok.text)
+		return
+	default:
+		operator, value = splitOperator(tok.text)
+	}
+
+	switch operator {
+	case "":
+		if tok.quoted {
+			q.Phrase(value)
+		} else {
+			q.Terms(value)
+		}
+	case "-":
+		q.Exclude(value)
+	case "site:":
+		q.Site(value)
+	case "-site:":
+		q.ExcludeSite(value)
+	case "filetype:":
+		q.FileType(value)
+	case "intitle:":
+		q.InTitle(value)
+	case "inurl:":
+		q.InURL(value)
+	default:
+		q.fail("unsupported operator %s", operator)
+	}
+}
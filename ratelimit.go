@@ -0,0 +1,112 @@
+package ddg_search
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter throttles requests to the DuckDuckGo endpoints. Implementations
+// must be safe for concurrent use; the same limiter may be shared by several
+// DDGS instances to throttle a whole process.
+type RateLimiter interface {
+	// Wait blocks until a request to endpoint may be sent or ctx is done
+	Wait(ctx context.Context, endpoint Endpoint) error
+}
+
+// WithRateLimiter replaces the default rate limiter of the DDGS client.
+// Passing the same limiter to several clients makes them share its budget.
+func WithRateLimiter(limiter RateLimiter) func(*DDGS) {
+	return func(d *DDGS) {
+		d.limiter = limiter
+	}
+}
+
+// TokenBucket is the default RateLimiter. Every endpoint configured with
+// SetEndpoint gets its own bucket, all other endpoints share the default one.
+type TokenBucket struct {
+	mu        sync.Mutex
+	fallback  *bucket
+	endpoints map[Endpoint]*bucket
+}
+
+// NewTokenBucket returns a limiter that allows one request per interval with
+// bursts of up to burst requests. An interval <= 0 disables throttling.
+func NewTokenBucket(interval time.Duration, burst int) *TokenBucket {
+	return &TokenBucket{
+		fallback:  newBucket(interval, burst),
+		endpoints: map[Endpoint]*bucket{},
+	}
+}
+
+// SetEndpoint gives endpoint its own bucket allowing one request per interval
+// with bursts of up to burst requests. It returns b for chaining.
+func (b *TokenBucket) SetEndpoint(endpoint Endpoint, interval time.Duration, burst int) *TokenBucket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.endpoints[endpoint] = newBucket(interval, burst)
+	return b
+}
+
+// Wait implements RateLimiter
+func (b *TokenBucket) Wait(ctx context.Context, endpoint Endpoint) error {
+	b.mu.Lock()
+	bk, ok := b.endpoints[endpoint]
+	if !ok {
+		bk = b.fallback
+	}
+	b.mu.Unlock()
+	return bk.wait(ctx)
+}
+
+// bucket is a single token bucket. Tokens may go negative, which reserves
+// future tokens for callers that are already waiting.
+type bucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(interval time.Duration, burst int) *bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{
+		interval: interval,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+	}
+}
+
+func (b *bucket) wait(ctx context.Context) error {
+	if b.interval <= 0 {
+		return ctx.Err()
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	if !b.last.IsZero() {
+		b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens * float64(b.interval))
+	}
+	b.mu.Unlock()
+
+	if err := wait(ctx, delay); err != nil {
+		// Give the reserved token back to the callers queued behind us
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package ddg_search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Region is a DuckDuckGo region code such as "de-de" or "us-en". Codes are
+// country first, then language; Regions lists every supported code and
+// RegionFromLocale maps BCP-47 locales onto them.
+type Region string
+
+// RegionWorldwide searches without a region and is the default of every
+// vertical
+const RegionWorldwide Region = "wt-wt"
+
+// regionInfo describes one entry of the region catalogue
+type regionInfo struct {
+	region Region
+	name   string
+	locale string // BCP-47 locale, empty for RegionWorldwide
+}
+
+// regions is the catalogue of DuckDuckGo regions, in the order of the
+// region menu
+var regions = []regionInfo{
+	{RegionWorldwide, "No region", ""},
+	{"xa-ar", "Arabia", "ar"},
+	{"xa-en", "Arabia (en)", "en-XA"},
+	{"ar-es", "Argentina", "es-AR"},
+	{"au-en", "Australia", "en-AU"},
+	{"at-de", "Austria", "de-AT"},
+	{"be-fr", "Belgium (fr)", "fr-BE"},
+	{"be-nl", "Belgium (nl)", "nl-BE"},
+	{"br-pt", "Brazil", "pt-BR"},
+	{"bg-bg", "Bulgaria", "bg-BG"},
+	{"ca-en", "Canada", "en-CA"},
+	{"ca-fr", "Canada (fr)", "fr-CA"},
+	{"ct-ca", "Catalan", "ca-ES"},
+	{"cl-es", "Chile", "es-CL"},
+	{"cn-zh", "China", "zh-CN"},
+	{"co-es", "Colombia", "es-CO"},
+	{"hr-hr", "Croatia", "hr-HR"},
+	{"cz-cs", "Czech Republic", "cs-CZ"},
+	{"dk-da", "Denmark", "da-DK"},
+	{"ee-et", "Estonia", "et-EE"},
+	{"fi-fi", "Finland", "fi-FI"},
+	{"fr-fr", "France", "fr-FR"},
+	{"de-de", "Germany", "de-DE"},
+	{"gr-el", "Greece", "el-GR"},
+	{"hk-tzh", "Hong Kong", "zh-HK"},
+	{"hu-hu", "Hungary", "hu-HU"},
+	{"in-en", "India", "en-IN"},
+	{"id-id", "Indonesia", "id-ID"},
+	{"id-en", "Indonesia (en)", "en-ID"},
+	{"ie-en", "Ireland", "en-IE"},
+	{"il-he", "Israel", "he-IL"},
+	{"it-it", "Italy", "it-IT"},
+	{"jp-jp", "Japan", "ja-JP"},
+	{"kr-kr", "Korea", "ko-KR"},
+	{"lv-lv", "Latvia", "lv-LV"},
+	{"lt-lt", "Lithuania", "lt-LT"},
+	{"xl-es", "Latin America", "es-419"},
+	{"my-ms", "Malaysia", "ms-MY"},
+	{"my-en", "Malaysia (en)", "en-MY"},
+	{"mx-es", "Mexico", "es-MX"},
+	{"nl-nl", "Netherlands", "nl-NL"},
+	{"nz-en", "New Zealand", "en-NZ"},
+	{"no-no", "Norway", "nb-NO"},
+	{"pe-es", "Peru", "es-PE"},
+	{"ph-en", "Philippines", "en-PH"},
+	{"ph-tl", "Philippines (tl)", "fil-PH"},
+	{"pl-pl", "Poland", "pl-PL"},
+	{"pt-pt", "Portugal", "pt-PT"},
+	{"ro-ro", "Romania", "ro-RO"},
+	{"ru-ru", "Russia", "ru-RU"},
+	{"sg-en", "Singapore", "en-SG"},
+	{"sk-sk", "Slovak Republic", "sk-SK"},
+	{"sl-sl", "Slovenia", "sl-SI"},
+	{"za-en", "South Africa", "en-ZA"},
+	{"es-es", "Spain", "es-ES"},
+	{"se-sv", "Sweden", "sv-SE"},
+	{"ch-de", "Switzerland (de)", "de-CH"},
+	{"ch-fr", "Switzerland (fr)", "fr-CH"},
+	{"ch-it", "Switzerland (it)", "it-CH"},
+	{"tw-tzh", "Taiwan", "zh-TW"},
+	{"th-th", "Thailand", "th-TH"},
+	{"tr-tr", "Turkey", "tr-TR"},
+	{"ua-uk", "Ukraine", "uk-UA"},
+	{"uk-en", "United Kingdom", "en-GB"},
+	{"us-en", "United States", "en-US"},
+	{"ue-es", "United States (es)", "es-US"},
+	{"ve-es", "Venezuela", "es-VE"},
+	{"vn-vi", "Vietnam", "vi-VN"},
+}
+
+// localeAliases maps locales that differ from the catalogue entry onto it
+var localeAliases = map[string]Region{
+	"nn-no":   "no-no",
+	"tl-ph":   "ph-tl",
+	"zh-hans": "cn-zh",
+	"zh-hant": "tw-tzh",
+}
+
+// Regions returns every supported region, starting with RegionWorldwide
+func Regions() []Region {
+	list := make([]Region, len(regions))
+	for i, info := range regions {
+		list[i] = info.region
+	}
+	return list
+}
+
+// info returns the catalogue entry of r
+func (r Region) info() (regionInfo, bool) {
+	for _, info := range regions {
+		if info.region == r {
+			return info, true
+		}
+	}
+	return regionInfo{}, false
+}
+
+// Valid reports whether r is one of Regions
+func (r Region) Valid() bool {
+	_, ok := r.info()
+	return ok
+}
+
+// Name returns the human-readable name of r, e.g. "Germany", or "" for an
+// unknown region
+func (r Region) Name() string {
+	info, _ := r.info()
+	return info.name
+}
+
+// Locale returns the BCP-47 locale of r, e.g. "de-DE", or "" for
+// RegionWorldwide and unknown regions
+func (r Region) Locale() string {
+	info, _ := r.info()
+	return info.locale
+}
+
+// RegionFromLocale returns the region of a BCP-47 locale such as "de-DE",
+// "en_GB" or "zh-Hant-TW". DuckDuckGo region codes are accepted as well.
+// Unknown locales return ErrInvalidParams.
+func RegionFromLocale(locale string) (Region, error) {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if r := Region(tag); r.Valid() {
+		return r, nil
+	}
+	if r, ok := localeAliases[tag]; ok {
+		return r, nil
+	}
+	// Drop a script subtag: zh-hant-tw becomes zh-tw
+	if parts := strings.Split(tag, "-"); len(parts) == 3 && len(parts[1]) == 4 {
+		tag = parts[0] + "-" + parts[2]
+	}
+	for _, info := range regions {
+		if info.locale != "" && strings.ToLower(info.locale) == tag {
+			return info.region, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no region for locale %q", ErrInvalidParams, locale)
+}
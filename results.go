@@ -0,0 +1,186 @@
+package ddg_search
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TextResult is a single result returned by Text
+type TextResult struct {
+	Title string `json:"title"`
+	Href  string `json:"href"`
+	Body  string `json:"body"`
+}
+
+// ImageResult is a single result returned by Images
+type ImageResult struct {
+	Title     string `json:"title"`
+	Image     string `json:"image"`
+	Thumbnail string `json:"thumbnail"`
+	URL       string `json:"url"`
+	Height    int    `json:"height"`
+	Width     int    `json:"width"`
+	Source    string `json:"source"`
+	// Raw holds the decoded JSON item as returned by DuckDuckGo
+	Raw map[string]interface{} `json:"raw,omitempty"`
+}
+
+// NewsResult is a single result returned by News
+type NewsResult struct {
+	Date   time.Time `json:"date"`
+	Title  string    `json:"title"`
+	Body   string    `json:"body"`
+	URL    string    `json:"url"`
+	Image  string    `json:"image"`
+	Source string    `json:"source"`
+	// Raw holds the decoded JSON item as returned by DuckDuckGo
+	Raw map[string]interface{} `json:"raw,omitempty"`
+}
+
+// VideoImages holds the preview images of a video result
+type VideoImages struct {
+	Large  string `json:"large"`
+	Medium string `json:"medium"`
+	Motion string `json:"motion"`
+	Small  string `json:"small"`
+}
+
+// VideoResult is a single result returned by Videos
+type VideoResult struct {
+	Content     string        `json:"content"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Duration    time.Duration `json:"duration"`
+	EmbedHTML   string        `json:"embed_html"`
+	EmbedURL    string        `json:"embed_url"`
+	ImageToken  string        `json:"image_token"`
+	Images      VideoImages   `json:"images"`
+	Provider    string        `json:"provider"`
+	Published   time.Time     `json:"published"`
+	Publisher   string        `json:"publisher"`
+	Uploader    string        `json:"uploader"`
+	ViewCount   int           `json:"view_count"`
+	// Raw holds the decoded JSON item as returned by DuckDuckGo
+	Raw map[string]interface{} `json:"raw,omitempty"`
+}
+
+// newImageResult converts a decoded i.js item into an ImageResult
+func newImageResult(item map[string]interface{}) ImageResult {
+	return ImageResult{
+		Title:     stringField(item, "title"),
+		Image:     stringField(item, "image"),
+		Thumbnail: stringField(item, "thumbnail"),
+		URL:       stringField(item, "url"),
+		Height:    intField(item, "height"),
+		Width:     intField(item, "width"),
+		Source:    stringField(item, "source"),
+		Raw:       item,
+	}
+}
+
+// newNewsResult converts a decoded news.js item into a NewsResult
+func newNewsResult(item map[string]interface{}) NewsResult {
+	var date time.Time
+	if ts := intField(item, "date"); ts > 0 {
+		date = time.Unix(int64(ts), 0).UTC()
+	}
+	return NewsResult{
+		Date:   date,
+		Title:  stringField(item, "title"),
+		Body:   stringField(item, "excerpt"),
+		URL:    stringField(item, "url"),
+		Image:  stringField(item, "image"),
+		Source: stringField(item, "source"),
+		Raw:    item,
+	}
+}
+
+// newVideoResult converts a decoded v.js item into a VideoResult
+func newVideoResult(item map[string]interface{}) VideoResult {
+	result := VideoResult{
+		Content:     stringField(item, "content"),
+		Title:       stringField(item, "title"),
+		Description: stringField(item, "description"),
+		Duration:    parseVideoDuration(stringField(item, "duration")),
+		EmbedHTML:   stringField(item, "embed_html"),
+		EmbedURL:    stringField(item, "embed_url"),
+		ImageToken:  stringField(item, "image_token"),
+		Provider:    stringField(item, "provider"),
+		Published:   parseVideoPublished(stringField(item, "published")),
+		Publisher:   stringField(item, "publisher"),
+		Uploader:    stringField(item, "uploader"),
+		Raw:         item,
+	}
+	if images, ok := item["images"].(map[string]interface{}); ok {
+		result.Images = VideoImages{
+			Large:  stringField(images, "large"),
+			Medium: stringField(images, "medium"),
+			Motion: stringField(images, "motion"),
+			Small:  stringField(images, "small"),
+		}
+	}
+	if stats, ok := item["statistics"].(map[string]interface{}); ok {
+		result.ViewCount = intField(stats, "viewCount")
+	}
+	return result
+}
+
+// stringField returns item[key] if it is a string
+func stringField(item map[string]interface{}, key string) string {
+	s, _ := item[key].(string)
+	return s
+}
+
+// intField returns item[key] as an int, accepting JSON numbers and numeric strings
+func intField(item map[string]interface{}, key string) int {
+	switch v := item[key].(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+// floatField returns item[key] as a float64, accepting JSON numbers and numeric strings
+func floatField(item map[string]interface{}, key string) float64 {
+	switch v := item[key].(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+// parseVideoDuration parses durations such as "4:05" or "1:02:03"
+func parseVideoDuration(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	var total time.Duration
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0
+		}
+		total = total*60 + time.Duration(n)
+	}
+	return total * time.Second
+}
+
+// parseVideoPublished parses the publication date of a video result
+func parseVideoPublished(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.0000000", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,137 @@
+package ddg_search
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. It applies to every
+// request, including VQD lookups and page fetches. The zero value disables
+// retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values <= 1 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry
+	BaseDelay time.Duration
+	// MaxDelay caps every single delay, including Retry-After values.
+	// Zero means no cap.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after each attempt (default 2)
+	Multiplier float64
+	// Jitter randomizes each delay by up to ±Jitter (0 to 1) of its value
+	Jitter float64
+
+	// RetryOnTimeout retries requests that failed with ErrTimeout
+	RetryOnTimeout bool
+	// RetryOnRatelimit retries requests that failed with ErrRatelimit
+	RetryOnRatelimit bool
+	// RetryOnServerError retries requests answered with a 5xx status
+	RetryOnServerError bool
+	// RespectRetryAfter waits for the Retry-After header when the response
+	// carries one and it is longer than the computed backoff
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy retries timeouts, rate limits and server errors up to
+// three times with exponential backoff
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:        4,
+	BaseDelay:          2 * time.Second,
+	MaxDelay:           30 * time.Second,
+	Multiplier:         2,
+	Jitter:             0.2,
+	RetryOnTimeout:     true,
+	RetryOnRatelimit:   true,
+	RetryOnServerError: true,
+	RespectRetryAfter:  true,
+}
+
+// WithRetryPolicy sets the retry policy of the DDGS client
+func WithRetryPolicy(policy RetryPolicy) func(*DDGS) {
+	return func(d *DDGS) {
+		d.retryPolicy = policy
+	}
+}
+
+// shouldRetry reports whether err may be retried after the given attempt
+func (p RetryPolicy) shouldRetry(err error, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	var statusErr *StatusError
+	switch {
+	case errors.Is(err, ErrTimeout):
+		return p.RetryOnTimeout
+	case errors.Is(err, ErrRatelimit):
+		return p.RetryOnRatelimit
+	case errors.As(err, &statusErr):
+		return p.RetryOnServerError && statusErr.StatusCode >= 500
+	}
+	return false
+}
+
+// delay returns how long to wait before the attempt following attempt
+func (p RetryPolicy) delay(err error, attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	backoff := float64(p.BaseDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.Jitter > 0 {
+		backoff *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	if p.MaxDelay > 0 && backoff > float64(p.MaxDelay) {
+		backoff = float64(p.MaxDelay)
+	}
+	wait := time.Duration(backoff)
+
+	var statusErr *StatusError
+	if p.RespectRetryAfter && errors.As(err, &statusErr) && statusErr.RetryAfter > wait {
+		wait = statusErr.RetryAfter
+		if p.MaxDelay > 0 && wait > p.MaxDelay {
+			wait = p.MaxDelay
+		}
+	}
+	return wait
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// wait blocks for d or until ctx is done
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,47 @@
+package ddg_search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Suggestion is a query suggested by DuckDuckGo's autocomplete
+type Suggestion struct {
+	Phrase string `json:"phrase"`
+}
+
+// Suggestions returns the autocomplete suggestions for query
+func (d *DDGS) Suggestions(query string, region string) ([]Suggestion, error) {
+	return d.SuggestionsContext(context.Background(), query, region)
+}
+
+// SuggestionsContext is like Suggestions but uses ctx for the rate limit
+// wait and the request
+func (d *DDGS) SuggestionsContext(ctx context.Context, query string, region string) ([]Suggestion, error) {
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is mandatory", ErrInvalidParams)
+	}
+	var p problems
+	p.region(Region(region))
+	if err := p.err(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"q":  {query},
+		"kl": {string(defaultRegion(Region(region)))},
+	}
+	var items []Suggestion
+	if err := d.getJSON(ctx, EndpointSuggestions, params, d.profile(d.pickProfile()), &items); err != nil {
+		return nil, err
+	}
+
+	var suggestions []Suggestion
+	for _, item := range items {
+		if item.Phrase != "" {
+			suggestions = append(suggestions, item)
+		}
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestAnswers(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	answer, err := ddgs.Answers("golang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer.Raw["meta"] == nil {
+		t.Fatal("raw response not preserved")
+	}
+	answer.Raw = nil
+
+	want := &ddg_search.InstantAnswer{
+		Heading:        "Go (programming language)",
+		Type:           "A",
+		Abstract:       "Go is a high-level general purpose programming language that is statically typed and compiled. It is known for the simplicity of its syntax and the efficiency of development that it enables through the inclusion of a large standard library.",
+		AbstractSource: "Wikipedia",
+		AbstractURL:    "https://en.wikipedia.org/wiki/Go_(programming_language)",
+		Image:          "https://duckduckgo.com/i/b8b2b5b8.png",
+		Entity:         "programming language",
+		Infobox: []ddg_search.InfoboxEntry{
+			{Label: "Paradigm", Value: "Multi-paradigm: concurrent imperative, functional, object-oriented", DataType: "string"},
+			{Label: "Designed by", Value: "Robert Griesemer, Rob Pike, Ken Thompson", DataType: "string"},
+			{Label: "First appeared", Value: "November 10, 2009", DataType: "string"},
+			{Label: "Twitter profile", Value: "golang", DataType: "twitter_profile"},
+			{Label: "Instance of", DataType: "instance"},
+		},
+		RelatedTopics: []ddg_search.RelatedTopic{
+			{
+				Text: "Go Playground - An online service that compiles and runs Go programs.",
+				URL:  "https://duckduckgo.com/Go_Playground",
+				Icon: "https://duckduckgo.com/i/1f0e2b5a.png",
+			},
+			{
+				Text: "Gopher - The mascot of the Go programming language.",
+				URL:  "https://duckduckgo.com/Gopher",
+			},
+			{
+				Name: "See also",
+				Topics: []ddg_search.RelatedTopic{
+					{Text: "C programming language family", URL: "https://duckduckgo.com/c/C_programming_language_family"},
+					{Text: "Concurrent programming languages", URL: "https://duckduckgo.com/c/Concurrent_programming_languages"},
+				},
+			},
+		},
+		Results: []ddg_search.RelatedTopic{
+			{Text: "Official site", URL: "https://go.dev/", Icon: "https://duckduckgo.com/i/go.dev.ico"},
+		},
+	}
+	if !reflect.DeepEqual(answer, want) {
+		t.Fatalf("unexpected answer:\n got %+v\nwant %+v", answer, want)
+	}
+
+	if n := srv.Requests(ddgtest.PathAnswers); n != 1 {
+		t.Fatalf("expected one API request, got %d", n)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 0 {
+		t.Fatalf("expected no VQD request, got %d", n)
+	}
+}
+
+func TestAnswersEmpty(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	answer, err := ddgs.Answers(ddgtest.QueryNoResults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer.Heading != "" || answer.Abstract != "" || answer.Infobox != nil || answer.RelatedTopics != nil {
+		t.Fatalf("expected an empty answer, got %+v", answer)
+	}
+}
+
+func TestAnswersErrors(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	if _, err := ddgs.Answers(""); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	if _, err := ddgs.Answers(ddgtest.QueryMalformed); !errors.Is(err, ddg_search.ErrSearch) {
+		t.Fatalf("expected ErrSearch for a malformed response, got %v", err)
+	}
+
+	srv.SetStatus(ddgtest.PathAnswers, http.StatusTooManyRequests)
+	if _, err := ddgs.Answers("golang"); !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ddgs.AnswersContext(ctx, "golang"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAnswersUsesRateLimiter(t *testing.T) {
+	limiter := &recordingLimiter{}
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithRateLimiter(limiter))
+
+	if _, err := ddgs.Answers("golang"); err != nil {
+		t.Fatal(err)
+	}
+	if got := limiter.endpoints; !reflect.DeepEqual(got, []ddg_search.Endpoint{ddg_search.EndpointAnswers}) {
+		t.Fatalf("expected the limiter to see the answers endpoint, got %v", got)
+	}
+}
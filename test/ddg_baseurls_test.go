@@ -0,0 +1,45 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+)
+
+func TestBaseURLs(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<script>vqd="4-123456789"</script>`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html>blocked</html>"))
+		}
+	}))
+	defer srv.Close()
+
+	ddgs := ddg_search.NewDDGS(
+		ddg_search.WithSleepDuration(0),
+		ddg_search.WithBaseURLs(ddg_search.BaseURLs{
+			VQD:    srv.URL + "/",
+			Images: srv.URL + "/i.js",
+		}),
+	)
+
+	_, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 5)
+	if !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+	var statusErr *ddg_search.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected StatusError with status 403, got %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/" || paths[1] != "/i.js" {
+		t.Fatalf("unexpected request paths %v", paths)
+	}
+}
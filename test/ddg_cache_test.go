@@ -0,0 +1,234 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func cachedText(ctx context.Context, ddgs *ddg_search.DDGS) ([]ddg_search.TextResult, error) {
+	return ddgs.TextContext(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendHTML, 20)
+}
+
+func TestCacheServesRepeatedSearch(t *testing.T) {
+	cache := ddg_search.NewLRUCache(100, time.Hour)
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithCache(cache))
+
+	var first ddg_search.CacheStats
+	want, err := cachedText(ddg_search.ContextWithCacheStats(context.Background(), &first), ddgs)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	requests := srv.TotalRequests()
+	if first.Hits != 0 || first.Misses != 2 {
+		t.Fatalf("expected 2 misses, got %+v", first)
+	}
+
+	var second ddg_search.CacheStats
+	got, err := cachedText(ddg_search.ContextWithCacheStats(context.Background(), &second), ddgs)
+	if err != nil {
+		t.Fatalf("cached search failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the cached results to match\nwant %+v\ngot  %+v", want, got)
+	}
+	if second.Hits != 2 || second.Misses != 0 {
+		t.Fatalf("expected 2 hits, got %+v", second)
+	}
+	if srv.TotalRequests() != requests {
+		t.Fatalf("expected no new requests, got %d", srv.TotalRequests()-requests)
+	}
+}
+
+func TestCacheKeyIncludesParameters(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithCache(ddg_search.NewLRUCache(100, 0)))
+
+	search := func(region string, timelimit ddg_search.Timelimit) {
+		t.Helper()
+		_, err := ddgs.Images("golang", region, ddg_search.SafeSearchModerate, timelimit, 0)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+	}
+	search("wt-wt", ddg_search.TimelimitAll)
+	search("de-de", ddg_search.TimelimitAll)
+	search("wt-wt", ddg_search.TimelimitWeek)
+	search("wt-wt", ddg_search.TimelimitAll)
+	if n := srv.Requests(ddgtest.PathImages); n != 3 {
+		t.Fatalf("expected one request per distinct search, got %d", n)
+	}
+}
+
+func TestCachePolicies(t *testing.T) {
+	cache := ddg_search.NewLRUCache(100, 0)
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithCache(cache))
+
+	bypass := ddg_search.ContextWithCachePolicy(context.Background(), ddg_search.CacheBypass)
+	if _, err := cachedText(bypass, ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if cache.Len() != 0 {
+		t.Fatalf("expected CacheBypass not to store pages, got %d", cache.Len())
+	}
+
+	if _, err := cachedText(context.Background(), ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	requests := srv.Requests(ddgtest.PathHTML)
+
+	var stats ddg_search.CacheStats
+	refresh := ddg_search.ContextWithCacheStats(
+		ddg_search.ContextWithCachePolicy(context.Background(), ddg_search.CacheRefresh), &stats)
+	if _, err := cachedText(refresh, ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if n := srv.Requests(ddgtest.PathHTML); n != requests+2 {
+		t.Fatalf("expected CacheRefresh to fetch every page again, got %d requests", n)
+	}
+	if stats.Hits != 0 || stats.Misses != 2 || cache.Len() != 2 {
+		t.Fatalf("expected CacheRefresh to replace the 2 cached pages, got %+v and %d pages", stats, cache.Len())
+	}
+}
+
+func TestCacheSkipsErrors(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithCache(ddg_search.NewLRUCache(100, 0)))
+	srv.SetStatus(ddgtest.PathHTML, http.StatusTooManyRequests)
+	if _, err := cachedText(context.Background(), ddgs); !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+
+	srv.SetStatus(ddgtest.PathHTML, 0)
+	results, err := cachedText(context.Background(), ddgs)
+	if err != nil || len(results) == 0 {
+		t.Fatalf("expected results after the failure, got %v, %v", results, err)
+	}
+}
+
+func TestCacheResumesCursor(t *testing.T) {
+	cache := ddg_search.NewLRUCache(100, 0)
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithCache(cache))
+
+	_, next, err := ddgs.NewsPage(context.Background(), "golang", "wt-wt",
+		ddg_search.SafeSearchModerate, ddg_search.TimelimitAll)
+	if err != nil || next == nil {
+		t.Fatalf("first page failed: %v", err)
+	}
+	want, _, err := ddgs.ResumeNews(context.Background(), next)
+	if err != nil {
+		t.Fatalf("second page failed: %v", err)
+	}
+	requests := srv.TotalRequests()
+
+	// A cursor restored from its string form still finds the cached page
+	c, err := ddg_search.ParseCursor(next.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := ddgs.ResumeNews(context.Background(), c)
+	if err != nil {
+		t.Fatalf("cached second page failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatal("expected the cached page to match")
+	}
+	if srv.TotalRequests() != requests {
+		t.Fatalf("expected the page to come from the cache, got %d new requests", srv.TotalRequests()-requests)
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := ddg_search.NewLRUCache(2, 0)
+	key := func(page int) ddg_search.CacheKey {
+		return ddg_search.CacheKey{Vertical: "text", Keywords: "golang", Page: page}
+	}
+	cache.Set(key(0), []byte("0"))
+	cache.Set(key(1), []byte("1"))
+	cache.Get(key(0))
+	cache.Set(key(2), []byte("2"))
+
+	if _, ok := cache.Get(key(1)); ok {
+		t.Fatal("expected the least recently used page to be evicted")
+	}
+	for _, page := range []int{0, 2} {
+		if _, ok := cache.Get(key(page)); !ok {
+			t.Fatalf("expected page %d to be cached", page)
+		}
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	cache := ddg_search.NewLRUCache(10, 20*time.Millisecond)
+	key := ddg_search.CacheKey{Vertical: "text", Keywords: "golang"}
+	cache.Set(key, []byte("page"))
+	if _, ok := cache.Get(key); !ok {
+		t.Fatal("expected a hit")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("expected the page to expire")
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache, err := ddg_search.NewFileCache(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithCache(cache))
+	want, err := cachedText(context.Background(), ddgs)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	requests := srv.TotalRequests()
+
+	// A new client with a new cache on the same directory, as after a restart
+	reopened, err := ddg_search.NewFileCache(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted := ddg_search.NewDDGS(ddg_search.WithBaseURLs(srv.BaseURLs()),
+		ddg_search.WithSleepDuration(0), ddg_search.WithCache(reopened))
+	var stats ddg_search.CacheStats
+	got, err := cachedText(ddg_search.ContextWithCacheStats(context.Background(), &stats), restarted)
+	if err != nil {
+		t.Fatalf("cached search failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) || stats.Hits != 2 || srv.TotalRequests() != requests {
+		t.Fatalf("expected the results to come from disk, got %+v and %d new requests", stats, srv.TotalRequests()-requests)
+	}
+}
+
+func TestFileCacheExpiry(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := ddg_search.NewFileCache(dir, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := ddg_search.CacheKey{Vertical: "news", Keywords: "golang", Page: 1}
+	cache.Set(key, []byte("page"))
+	if data, ok := cache.Get(key); !ok || string(data) != "page" {
+		t.Fatalf("expected a hit, got %q, %v", data, ok)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected one cache file, got %v", files)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(files[0], old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("expected the page to expire")
+	}
+}
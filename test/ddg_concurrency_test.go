@@ -0,0 +1,118 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+// TestConcurrentSearches runs many mixed-vertical searches in parallel on a
+// single client. Run it with -race.
+func TestConcurrentSearches(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	const rounds = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, rounds*5)
+
+	for i := 0; i < rounds; i++ {
+		wg.Add(5)
+		go func() {
+			defer wg.Done()
+			results, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+				ddg_search.TimelimitAll, ddg_search.BackendHTML, 10)
+			want := []ddg_search.TextResult{textGoDev, textWikipedia, textPkgGoDev, textGitHub}
+			if err == nil && !reflect.DeepEqual(results, want) {
+				err = fmt.Errorf("html: unexpected results %+v", results)
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			results, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+				ddg_search.TimelimitAll, ddg_search.BackendLite, 10)
+			want := []ddg_search.TextResult{textGoDev, textWikipedia, textGitHub}
+			if err == nil && !reflect.DeepEqual(results, want) {
+				err = fmt.Errorf("lite: unexpected results %+v", results)
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			results, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+			if err == nil && len(results) != 3 {
+				err = fmt.Errorf("images: expected 3 results, got %d", len(results))
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			results, err := ddgs.News("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+			if err == nil && len(results) != 3 {
+				err = fmt.Errorf("news: expected 3 results, got %d", len(results))
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			results, err := ddgs.Videos("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll,
+				ddg_search.ResolutionAll, ddg_search.DurationAll, ddg_search.LicenseAll, 10)
+			if err == nil && len(results) != 2 {
+				err = fmt.Errorf("videos: expected 2 results, got %d", len(results))
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Every request carries the Referer of its own endpoint
+	referers := map[string]string{
+		ddgtest.PathVQD:    "https://duckduckgo.com/",
+		ddgtest.PathHTML:   "https://html.duckduckgo.com/",
+		ddgtest.PathLite:   "https://lite.duckduckgo.com/",
+		ddgtest.PathImages: "https://duckduckgo.com/",
+		ddgtest.PathNews:   "https://duckduckgo.com/",
+		ddgtest.PathVideos: "https://duckduckgo.com/",
+	}
+	for path, want := range referers {
+		headers := srv.Headers(path)
+		if len(headers) == 0 {
+			t.Fatalf("no requests to %s", path)
+		}
+		for _, h := range headers {
+			if got := h.Get("Referer"); got != want {
+				t.Fatalf("%s: expected Referer %q, got %q", path, want, got)
+			}
+		}
+	}
+}
+
+func TestConfiguredHeadersApplyEverywhere(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithHeaders(map[string]string{"X-Team": "search"}))
+
+	if _, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendLite, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ddgs.News("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{ddgtest.PathLite, ddgtest.PathVQD, ddgtest.PathNews} {
+		for _, h := range srv.Headers(path) {
+			if h.Get("X-Team") != "search" {
+				t.Fatalf("%s: configured header missing", path)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestTextContextCanceled(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ddgs.TextContext(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendHTML, 5)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if n := srv.TotalRequests(); n != 0 {
+		t.Fatalf("expected no requests, got %d", n)
+	}
+}
+
+func TestRateLimitWaitHonorsContext(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithSleepDuration(time.Minute))
+
+	// The first request uses up the only token
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := ddgs.ImagesContext(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 5)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("rate limit wait was not interrupted, took %v", elapsed)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 1 {
+		t.Fatalf("expected only the VQD request, got %d", n)
+	}
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestTextCursorResume(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+	ctx := context.Background()
+
+	results, cursor, err := ddgs.TextPage(ctx, "golang", "wt-wt",
+		ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, ddg_search.BackendHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ddg_search.TextResult{textGoDev, textWikipedia}; !reflect.DeepEqual(results, want) {
+		t.Fatalf("unexpected first page:\n got %+v\nwant %+v", results, want)
+	}
+	if cursor == nil || cursor.Page() != 1 {
+		t.Fatalf("expected a cursor to page 1, got %v", cursor)
+	}
+
+	// Resume from the serialized cursor with a fresh client, as another
+	// process would
+	restored, err := ddg_search.ParseCursor(cursor.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := ddg_search.NewDDGS(ddg_search.WithBaseURLs(srv.BaseURLs()), ddg_search.WithSleepDuration(0))
+	results, cursor, err = other.ResumeText(ctx, restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ddg_search.TextResult{textPkgGoDev, textGitHub}; !reflect.DeepEqual(results, want) {
+		t.Fatalf("unexpected second page:\n got %+v\nwant %+v", results, want)
+	}
+	if cursor != nil {
+		t.Fatalf("expected no further cursor, got %v", cursor)
+	}
+}
+
+func TestTextCursorKeepsBackend(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+	srv.SetStatus(ddgtest.PathHTML, 202)
+	ctx := context.Background()
+
+	_, cursor, err := ddgs.TextPage(ctx, "golang", "wt-wt",
+		ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, ddg_search.BackendAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlRequests := srv.Requests(ddgtest.PathHTML)
+
+	results, _, err := ddgs.ResumeText(ctx, cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ddg_search.TextResult{textGitHub}; !reflect.DeepEqual(results, want) {
+		t.Fatalf("unexpected second page:\n got %+v\nwant %+v", results, want)
+	}
+	if n := srv.Requests(ddgtest.PathHTML); n != htmlRequests {
+		t.Fatalf("resumed lite cursor must not hit the html backend")
+	}
+}
+
+func TestNewsCursorJSON(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+	ctx := context.Background()
+
+	results, cursor, err := ddgs.NewsPage(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results on the first page, got %+v", results)
+	}
+
+	checkpoint, err := json.Marshal(struct {
+		Cursor *ddg_search.Cursor `json:"cursor"`
+	}{cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored struct {
+		Cursor *ddg_search.Cursor `json:"cursor"`
+	}
+	if err := json.Unmarshal(checkpoint, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	results, cursor, err = ddgs.ResumeNews(ctx, restored.Cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].URL != "https://go.dev/blog/generics" || cursor != nil {
+		t.Fatalf("unexpected second page %+v, cursor %v", results, cursor)
+	}
+	// The VQD token travels with the cursor
+	if n := srv.Requests(ddgtest.PathVQD); n != 1 {
+		t.Fatalf("expected 1 VQD request, got %d", n)
+	}
+}
+
+func TestCursorErrors(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+	ctx := context.Background()
+
+	_, cursor, err := ddgs.VideosPage(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll,
+		ddg_search.ResolutionAll, ddg_search.DurationAll, ddg_search.LicenseAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ddgs.ResumeImages(ctx, cursor); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams for a mismatched cursor, got %v", err)
+	}
+	if _, _, err := ddgs.ResumeText(ctx, nil); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams for a nil cursor, got %v", err)
+	}
+	if _, err := ddg_search.ParseCursor("not a cursor"); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams for a malformed cursor, got %v", err)
+	}
+}
@@ -1,31 +1,155 @@
 package test
 
 import (
-	"github.com/Patrick7241/ddg_search"
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
 	"testing"
-	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
 )
 
 func TestImagesDDG(t *testing.T) {
-	ddgs := ddg_search.NewDDGS(
-		ddg_search.WithProxy("127.0.0.1:7890"), // add proxy
-		ddg_search.WithTimeout(10*time.Second),
-		ddg_search.WithSleepDuration(10*time.Second),
-	)
-
-	// 文本搜索
-	results, err := ddgs.Images(
-		"golang",
-		"wt-wt",
-		ddg_search.SafeSearchModerate,
-		ddg_search.TimelimitAll,
-		1,
-	)
+	ddgs, srv := newFakeDDGS(t)
+
+	results, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 
-	for _, r := range results {
-		t.Logf("img: %v \n", r)
+	want := []ddg_search.ImageResult{
+		{
+			Title:     "The Go gopher",
+			Image:     "https://example.com/images/gopher.png",
+			Thumbnail: "https://tse1.mm.bing.net/th?id=OIP.gopher",
+			URL:       "https://example.com/gopher",
+			Height:    1080,
+			Width:     1920,
+			Source:    "Bing",
+		},
+		{
+			Title:     "Go logo",
+			Image:     "https://example.com/images/go-logo.svg",
+			Thumbnail: "https://tse2.mm.bing.net/th?id=OIP.logo",
+			URL:       "https://example.com/brand",
+			Height:    600,
+			Width:     800,
+			Source:    "Bing",
+		},
+		{
+			Title:     "Small gopher",
+			Image:     "https://example.com/images/gopher-small.png",
+			Thumbnail: "https://tse3.mm.bing.net/th?id=OIP.small",
+			URL:       "https://example.com/small",
+			Height:    512,
+			Width:     512,
+			Source:    "Bing",
+		},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d: %+v", len(want), len(results), results)
+	}
+	for i, r := range results {
+		if r.Raw == nil {
+			t.Fatalf("result %d: Raw is nil", i)
+		}
+		r.Raw = nil
+		if !reflect.DeepEqual(r, want[i]) {
+			t.Fatalf("result %d:\n got %+v\nwant %+v", i, r, want[i])
+		}
+	}
+	if token := results[0].Raw["image_token"]; token != "8f2a1c" {
+		t.Fatalf("expected raw image_token 8f2a1c, got %v", token)
+	}
+	if n := srv.Requests(ddgtest.PathImages); n != 2 {
+		t.Fatalf("expected 2 i.js requests, got %d", n)
+	}
+}
+
+func TestImagesFirstPageOnly(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	// maxResults == 0 only fetches the first page
+	results, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	if n := srv.Requests(ddgtest.PathImages); n != 1 {
+		t.Fatalf("expected 1 i.js request, got %d", n)
+	}
+}
+
+func TestImagesErrors(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	_, err := ddgs.Images(ddgtest.QueryRatelimit, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	if !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+
+	_, err = ddgs.Images(ddgtest.QueryMalformed, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	if !errors.Is(err, ddg_search.ErrSearch) {
+		t.Fatalf("expected ErrSearch, got %v", err)
+	}
+
+	results, err := ddgs.Images(ddgtest.QueryNoResults, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected no results, got %+v, %v", results, err)
+	}
+
+	srv.SetStatus(ddgtest.PathVQD, 403)
+	_, err = ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	var statusErr *ddg_search.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 403 || !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected 403 StatusError, got %v", err)
+	}
+}
+
+func TestImagesFilters(t *testing.T) {
+	var filters []string
+	recorder := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == ddgtest.PathImages {
+				filters = append(filters, req.URL.Query().Get("f"))
+			}
+			return next.RoundTrip(req)
+		})
+	}
+	cache := ddg_search.NewLRUCache(100, 0)
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithRoundTripperMiddleware(recorder), ddg_search.WithCache(cache))
+
+	search := func(timelimit ddg_search.Timelimit, f ddg_search.ImageFilters) {
+		t.Helper()
+		opts := ddg_search.ImagesOptions{Timelimit: timelimit, Filters: f}
+		if _, _, err := ddgs.ImagesPageWithOptions(context.Background(), "golang", opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	search(ddg_search.TimelimitAll, ddg_search.ImageFilters{})
+	search(ddg_search.TimelimitWeek, ddg_search.ImageFilters{
+		Size:    ddg_search.ImageSizeLarge,
+		Color:   ddg_search.ImageColorMonochrome,
+		Type:    ddg_search.ImageTypeTransparent,
+		Layout:  ddg_search.ImageLayoutWide,
+		License: ddg_search.ImageLicenseModifyCommercially,
+	})
+	search(ddg_search.TimelimitAll, ddg_search.ImageFilters{Color: ddg_search.ImageColorBlue})
+	// Filters are part of the cache key
+	search(ddg_search.TimelimitAll, ddg_search.ImageFilters{Color: ddg_search.ImageColorRed})
+	search(ddg_search.TimelimitAll, ddg_search.ImageFilters{Color: ddg_search.ImageColorBlue})
+
+	want := []string{
+		"",
+		"time:w,size:Large,color:Monochrome,type:transparent,layout:Wide,license:ModifyCommercially",
+		"color:Blue",
+		"color:Red",
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Fatalf("unexpected f parameters:\n got %q\nwant %q", filters, want)
 	}
 }
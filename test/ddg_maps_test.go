@@ -0,0 +1,195 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestMaps(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	places, err := ddgs.Maps("coffee", ddg_search.Location{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range places {
+		names = append(names, p.Name)
+	}
+	if want := []string{"Gopher Coffee", "Channel Café", "Goroutine Espresso Bar"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected deduplicated places from both pages %v, got %v", want, names)
+	}
+
+	first := places[0]
+	if first.Raw["id"] != "1001" {
+		t.Fatal("raw item not preserved")
+	}
+	first.Raw = nil
+	want := ddg_search.Place{
+		Name:        "Gopher Coffee",
+		Address:     "Torstraße 1, 10119 Berlin, Germany",
+		CountryCode: "DE",
+		Latitude:    52.5291,
+		Longitude:   13.4013,
+		Phone:       "+49 30 1234567",
+		Hours:       map[string]string{"Mon": "8:00AM - 6:00PM", "Tue": "8:00AM - 6:00PM"},
+		Rating:      4.5,
+		Reviews:     128,
+		Website:     "https://gopher.coffee/",
+		Category:    "Coffee Shops",
+		Description: "Small roastery serving espresso and filter coffee.",
+		Image:       "https://example.com/maps/gopher.jpg",
+		Source:      "https://www.yelp.com/biz/gopher-coffee-berlin",
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Fatalf("unexpected place:\n got %+v\nwant %+v", first, want)
+	}
+
+	// Numeric strings are accepted for coordinates, rating and reviews
+	second := places[1]
+	if second.Latitude != 52.5372 || second.Longitude != 13.4107 || second.Rating != 4.1 || second.Reviews != 37 {
+		t.Fatalf("unexpected numeric fields: %+v", second)
+	}
+
+	if n := srv.Requests(ddgtest.PathVQD); n != 1 {
+		t.Fatalf("expected one VQD request, got %d", n)
+	}
+}
+
+func TestMapsLocation(t *testing.T) {
+	var queries []url.Values
+	recorder := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == ddgtest.PathMaps {
+				queries = append(queries, req.URL.Query())
+			}
+			return next.RoundTrip(req)
+		})
+	}
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithRoundTripperMiddleware(recorder))
+	ctx := context.Background()
+
+	if _, _, err := ddgs.MapsPage(ctx, "coffee", ddg_search.Location{Place: "Berlin"}); err != nil {
+		t.Fatal(err)
+	}
+	if q := queries[0]; q.Get("q") != "coffee near Berlin" || q.Get("bbox_tl") != "" {
+		t.Fatalf("expected the place to be part of the query, got %v", q)
+	}
+
+	location := ddg_search.Location{Place: "ignored", Latitude: 52.52, Longitude: 13.405, Radius: 2}
+	if _, _, err := ddgs.MapsPage(ctx, "coffee", location); err != nil {
+		t.Fatal(err)
+	}
+	q := queries[1]
+	if q.Get("q") != "coffee" {
+		t.Fatalf("expected coordinates to take precedence over the place, got %q", q.Get("q"))
+	}
+	top, left := parseCorner(t, q.Get("bbox_tl"))
+	bottom, right := parseCorner(t, q.Get("bbox_br"))
+	// 2 km are about 0.018 degrees of latitude and, at 52.52 degrees north,
+	// 0.029 degrees of longitude
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"top", top, 52.538},
+		{"bottom", bottom, 52.502},
+		{"left", left, 13.375},
+		{"right", right, 13.435},
+	} {
+		if math.Abs(c.got-c.want) > 0.001 {
+			t.Fatalf("unexpected %s edge of the bounding box: %v, want about %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func parseCorner(t *testing.T, s string) (lat, lon float64) {
+	t.Helper()
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		t.Fatalf("malformed corner %q", s)
+	}
+	lat, err1 := strconv.ParseFloat(parts[0], 64)
+	lon, err2 := strconv.ParseFloat(parts[1], 64)
+	if err1 != nil || err2 != nil {
+		t.Fatalf("malformed corner %q", s)
+	}
+	return lat, lon
+}
+
+func TestMapsPagination(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	page, next, err := ddgs.MapsPage(context.Background(), "coffee", ddg_search.Location{})
+	if err != nil || next == nil {
+		t.Fatalf("first page failed: %v", err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("expected 2 places on the first page, got %d", len(page))
+	}
+
+	c, err := ddg_search.ParseCursor(next.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, next, err = ddgs.ResumeMaps(context.Background(), c)
+	if err != nil {
+		t.Fatalf("second page failed: %v", err)
+	}
+	if len(page) != 1 || page[0].Name != "Goroutine Espresso Bar" || next != nil {
+		t.Fatalf("unexpected second page: %+v, next %v", page, next)
+	}
+
+	if _, _, err := ddgs.ResumeNews(context.Background(), c); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected a maps cursor to be rejected by ResumeNews, got %v", err)
+	}
+
+	var count int
+	for _, err := range ddgs.MapsSeq(context.Background(), ddgtest.QueryManyPages, ddg_search.Location{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if count != ddgtest.ManyPagesCount {
+		t.Fatalf("expected %d places, got %d", ddgtest.ManyPagesCount, count)
+	}
+}
+
+func TestMapsErrors(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	for _, location := range []ddg_search.Location{
+		{Latitude: 91, Longitude: 0},
+		{Latitude: 0, Longitude: -181},
+		{Latitude: 52.52, Longitude: 13.405, Radius: -1},
+	} {
+		if _, err := ddgs.Maps("coffee", location, 10); !errors.Is(err, ddg_search.ErrInvalidParams) {
+			t.Fatalf("expected ErrInvalidParams for %+v, got %v", location, err)
+		}
+	}
+	if _, err := ddgs.Maps("", ddg_search.Location{}, 10); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams for empty keywords, got %v", err)
+	}
+	if srv.TotalRequests() != 0 {
+		t.Fatalf("expected invalid searches not to reach the server, got %d requests", srv.TotalRequests())
+	}
+
+	if _, err := ddgs.Maps(ddgtest.QueryMalformed, ddg_search.Location{}, 10); !errors.Is(err, ddg_search.ErrSearch) {
+		t.Fatalf("expected ErrSearch for a malformed response, got %v", err)
+	}
+	srv.SetStatus(ddgtest.PathMaps, http.StatusTooManyRequests)
+	if _, err := ddgs.Maps("coffee", ddg_search.Location{}, 10); !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+}
@@ -1,31 +1,80 @@
 package test
 
 import (
-	"github.com/Patrick7241/ddg_search"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
 )
 
 func TestNewsDDG(t *testing.T) {
-	ddgs := ddg_search.NewDDGS(
-		ddg_search.WithProxy("127.0.0.1:7890"), // add proxy
-		ddg_search.WithTimeout(10*time.Second),
-		ddg_search.WithSleepDuration(10*time.Second),
-	)
-
-	// 文本搜索
-	results, err := ddgs.News(
-		"golang",
-		"wt-wt",
-		ddg_search.SafeSearchModerate,
-		ddg_search.TimelimitAll,
-		1,
-	)
+	ddgs, srv := newFakeDDGS(t)
+
+	results, err := ddgs.News("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+
+	want := []ddg_search.NewsResult{
+		{
+			Date:   time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC),
+			Title:  "Go 1.21 is released",
+			Body:   "The Go team announced the release of Go 1.21.",
+			URL:    "https://go.dev/blog/go1.21",
+			Image:  "https://example.com/news/go121.jpg",
+			Source: "Go Blog",
+		},
+		{
+			Date:   time.Date(2023, 11, 14, 23, 13, 20, 0, time.UTC),
+			Title:  "Developer survey results",
+			Body:   "Developers rank Go among the most loved languages.",
+			URL:    "https://example.com/news/survey",
+			Source: "Tech Weekly",
+		},
+		{
+			Date:   time.Date(2023, 11, 15, 22, 13, 20, 0, time.UTC),
+			Title:  "Generics, one year on",
+			Body:   "A look at generics one year later.",
+			URL:    "https://go.dev/blog/generics",
+			Image:  "https://example.com/news/generics.jpg",
+			Source: "Go Blog",
+		},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d: %+v", len(want), len(results), results)
+	}
+	for i, r := range results {
+		if r.Raw["relative_time"] == nil {
+			t.Fatalf("result %d: raw item not preserved", i)
+		}
+		r.Raw = nil
+		if !reflect.DeepEqual(r, want[i]) {
+			t.Fatalf("result %d:\n got %+v\nwant %+v", i, r, want[i])
+		}
+	}
+	if n := srv.Requests(ddgtest.PathNews); n != 2 {
+		t.Fatalf("expected 2 news.js requests, got %d", n)
+	}
+}
+
+func TestNewsErrors(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	_, err := ddgs.News(ddgtest.QueryRatelimit, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	if !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+
+	_, err = ddgs.News(ddgtest.QueryMalformed, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	if !errors.Is(err, ddg_search.ErrSearch) {
+		t.Fatalf("expected ErrSearch, got %v", err)
 	}
 
-	for _, r := range results {
-		t.Logf("news: %v \n", r)
+	results, err := ddgs.News(ddgtest.QueryNoResults, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected no results, got %+v, %v", results, err)
 	}
 }
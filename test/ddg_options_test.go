@@ -0,0 +1,156 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	valid := []interface{ Validate() error }{
+		ddg_search.TextOptions{},
+		ddg_search.ImagesOptions{},
+		ddg_search.NewsOptions{},
+		ddg_search.VideosOptions{},
+		ddg_search.TextOptions{Region: "de-de", SafeSearch: ddg_search.SafeSearchOff, Timelimit: ddg_search.TimelimitYear, Backend: ddg_search.BackendLite, MaxResults: 30},
+		ddg_search.ImagesOptions{Filters: ddg_search.ImageFilters{Size: ddg_search.ImageSizeSmall, License: ddg_search.ImageLicensePublic}},
+		ddg_search.VideosOptions{Resolution: ddg_search.ResolutionHigh, Duration: ddg_search.DurationLong, License: ddg_search.LicenseYouTube},
+	}
+	for _, opts := range valid {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error %v", opts, err)
+		}
+	}
+
+	invalid := []struct {
+		opts interface{ Validate() error }
+		want []string
+	}{
+		{ddg_search.TextOptions{Backend: "api"}, []string{`backend "api"`}},
+		{ddg_search.ImagesOptions{Region: "de-DE"}, []string{`region "de-DE"`}},
+		{ddg_search.NewsOptions{SafeSearch: "strict", Timelimit: "h"}, []string{`safesearch "strict"`, `timelimit "h"`}},
+		{ddg_search.ImagesOptions{Filters: ddg_search.ImageFilters{Color: "Magenta", Layout: "Round"}}, []string{`image color "Magenta"`, `image layout "Round"`}},
+		{ddg_search.VideosOptions{Resolution: "4k", Duration: "epic", License: "gpl"}, []string{`resolution "4k"`, `duration "epic"`, `license "gpl"`}},
+	}
+	for _, tc := range invalid {
+		err := tc.opts.Validate()
+		if !errors.Is(err, ddg_search.ErrInvalidParams) {
+			t.Errorf("%+v: expected ErrInvalidParams, got %v", tc.opts, err)
+			continue
+		}
+		for _, detail := range tc.want {
+			if !strings.Contains(err.Error(), detail) {
+				t.Errorf("%+v: expected %q in %q", tc.opts, detail, err)
+			}
+		}
+	}
+}
+
+func TestOptionsDefaults(t *testing.T) {
+	var queries []url.Values
+	recorder := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == ddgtest.PathNews || req.URL.Path == ddgtest.PathVideos {
+				queries = append(queries, req.URL.Query())
+			}
+			return next.RoundTrip(req)
+		})
+	}
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithRoundTripperMiddleware(recorder))
+	ctx := context.Background()
+
+	if _, err := ddgs.NewsWithOptions(ctx, "golang", ddg_search.NewsOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if q := queries[0]; q.Get("l") != "wt-wt" || q.Get("p") != "-1" || q.Has("df") {
+		t.Fatalf("expected the default region, safesearch and timelimit, got %v", q)
+	}
+
+	before := len(queries)
+	_, err := ddgs.VideosWithOptions(ctx, "golang", ddg_search.VideosOptions{
+		Region:     "de-de",
+		SafeSearch: ddg_search.SafeSearchOff,
+		Duration:   ddg_search.DurationShort,
+		License:    ddg_search.LicenseCreativeCommon,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := queries[before]
+	if q.Get("l") != "de-de" || q.Get("p") != "-2" || q.Get("f") != "videoDuration:short,videoLicense:creativeCommon" {
+		t.Fatalf("unexpected videos query %v", q)
+	}
+
+	if _, err := ddgs.TextWithOptions(ctx, "golang", ddg_search.TextOptions{SafeSearch: "strict"}); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+}
+
+func TestOptionsMaxResults(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+	ctx := context.Background()
+
+	// The zero value applies no limit: every page is fetched
+	all, err := ddgs.NewsWithOptions(ctx, "golang", ddg_search.NewsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages := srv.Requests(ddgtest.PathNews)
+	if pages < 2 {
+		t.Fatalf("expected NewsOptions{} to fetch every page, got %d requests", pages)
+	}
+
+	// The positional wrapper keeps returning the first page only for 0
+	first, err := ddgs.News("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.Requests(ddgtest.PathNews) - pages; n != 1 || len(first) >= len(all) {
+		t.Fatalf("expected News(..., 0) to fetch one page, got %d requests and %d of %d results", n, len(first), len(all))
+	}
+
+	limited, err := ddgs.NewsWithOptions(ctx, "golang", ddg_search.NewsOptions{MaxResults: 1})
+	if err != nil || len(limited) != 1 {
+		t.Fatalf("expected a single result, got %d, %v", len(limited), err)
+	}
+}
+
+func TestOptionsMatchPositional(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+	ctx := context.Background()
+
+	positional, err := ddgs.TextContext(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendHTML, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withOptions, err := ddgs.TextWithOptions(ctx, "golang", ddg_search.TextOptions{Backend: ddg_search.BackendHTML, MaxResults: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(positional, withOptions) {
+		t.Fatal("expected the positional wrapper to return the same results")
+	}
+
+	page, next, err := ddgs.ImagesPageWithOptions(ctx, "golang", ddg_search.ImagesOptions{})
+	if err != nil || len(page) == 0 || next == nil {
+		t.Fatalf("unexpected first page: %d results, next %v, %v", len(page), next, err)
+	}
+	var count int
+	for _, err := range ddgs.NewsSeqWithOptions(ctx, ddgtest.QueryManyPages, ddg_search.NewsOptions{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if count != ddgtest.ManyPagesCount {
+		t.Fatalf("expected %d results, got %d", ddgtest.ManyPagesCount, count)
+	}
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestDefaultBrowserProfile(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+	if err := searchImages(t, ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	profile := ddg_search.ProfileChromeWindows
+	vqd := srv.Headers(ddgtest.PathVQD)[0]
+	api := srv.Headers(ddgtest.PathImages)[0]
+	for name, h := range map[string]http.Header{"vqd": vqd, "images": api} {
+		if got := h.Get("User-Agent"); got != profile.UserAgent {
+			t.Fatalf("%s: expected User-Agent %q, got %q", name, profile.UserAgent, got)
+		}
+		if got := h.Get("Sec-Ch-Ua-Platform"); got != profile.SecChUAPlatform {
+			t.Fatalf("%s: expected Sec-Ch-Ua-Platform %q, got %q", name, profile.SecChUAPlatform, got)
+		}
+		if got := h.Get("Accept-Language"); got != profile.AcceptLanguage {
+			t.Fatalf("%s: expected Accept-Language %q, got %q", name, profile.AcceptLanguage, got)
+		}
+	}
+	if vqd.Get("Sec-Fetch-Mode") != "navigate" || vqd.Get("Accept") != profile.Accept {
+		t.Fatalf("expected the VQD request to look like a page load, got %v", vqd)
+	}
+	if api.Get("Sec-Fetch-Mode") != "cors" || api.Get("Sec-Fetch-Dest") != "empty" {
+		t.Fatalf("expected the images request to look like a fetch, got %v", api)
+	}
+	if api.Get("Referer") != "https://duckduckgo.com/" {
+		t.Fatalf("expected the per-request Referer to be kept, got %q", api.Get("Referer"))
+	}
+}
+
+func TestTextSendsBrowserProfile(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithBrowserProfile(ddg_search.ProfileFirefoxLinux))
+	if err := searchHTML(ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	h := srv.Headers(ddgtest.PathHTML)[0]
+	if got := h.Get("User-Agent"); got != ddg_search.ProfileFirefoxLinux.UserAgent {
+		t.Fatalf("expected the Firefox User-Agent, got %q", got)
+	}
+	if h.Get("Sec-Ch-Ua") != "" {
+		t.Fatalf("expected no client hints from Firefox, got %q", h.Get("Sec-Ch-Ua"))
+	}
+	if h.Get("Sec-Fetch-User") != "?1" || h.Get("Referer") != "https://html.duckduckgo.com/" {
+		t.Fatalf("unexpected navigation headers %v", h)
+	}
+}
+
+func TestBrowserProfileRotationPerSession(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithBrowserProfileRotation(
+		ddg_search.ProfileFirefoxLinux, ddg_search.ProfileSafariMac))
+
+	seen := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		before := len(srv.Headers(ddgtest.PathNews))
+		_, err := ddgs.News("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 100)
+		if err != nil {
+			t.Fatalf("search %d failed: %v", i, err)
+		}
+
+		pages := srv.Headers(ddgtest.PathNews)[before:]
+		if len(pages) < 2 {
+			t.Fatalf("expected several pages, got %d", len(pages))
+		}
+		ua := pages[0].Get("User-Agent")
+		seen[ua] = true
+		for _, h := range pages {
+			if got := h.Get("User-Agent"); got != ua {
+				t.Fatalf("search %d: expected every page to use %q, got %q", i, ua, got)
+			}
+		}
+		// The token is fetched once per profile and only reused with it
+		vqds := srv.Headers(ddgtest.PathVQD)
+		if len(vqds) != len(seen) {
+			t.Fatalf("search %d: expected one VQD request per profile used, got %d for %d profiles", i, len(vqds), len(seen))
+		}
+		if !vqdUserAgents(vqds)[ua] {
+			t.Fatalf("search %d: pages sent with %q but no VQD request was", i, ua)
+		}
+	}
+	if len(seen) != 2 || !seen[ddg_search.ProfileFirefoxLinux.UserAgent] || !seen[ddg_search.ProfileSafariMac.UserAgent] {
+		t.Fatalf("expected both profiles to be used, got %v", seen)
+	}
+}
+
+// vqdUserAgents returns the User-Agents of the given VQD requests
+func vqdUserAgents(headers []http.Header) map[string]bool {
+	uas := map[string]bool{}
+	for _, h := range headers {
+		uas[h.Get("User-Agent")] = true
+	}
+	return uas
+}
+
+func TestBrowserProfileKeptByCursor(t *testing.T) {
+	rotation := ddg_search.WithBrowserProfileRotation()
+	ddgs, srv := newFakeDDGS(t, rotation)
+
+	_, next, err := ddgs.ImagesPage(context.Background(), "golang", "wt-wt",
+		ddg_search.SafeSearchModerate, ddg_search.TimelimitAll)
+	if err != nil || next == nil {
+		t.Fatalf("first page failed: %v", err)
+	}
+	first := srv.Headers(ddgtest.PathImages)[0].Get("User-Agent")
+
+	c, err := ddg_search.ParseCursor(next.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed := ddg_search.NewDDGS(ddg_search.WithBaseURLs(srv.BaseURLs()),
+		ddg_search.WithSleepDuration(0), rotation)
+	if _, _, err := resumed.ResumeImages(context.Background(), c); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if got := srv.Headers(ddgtest.PathImages)[1].Get("User-Agent"); got != first {
+		t.Fatalf("expected the resumed page to keep %q, got %q", first, got)
+	}
+}
+
+func TestWithHeadersOverridesBrowserProfile(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithHeaders(map[string]string{"User-Agent": "custom"}))
+	if err := searchImages(t, ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	for _, path := range []string{ddgtest.PathVQD, ddgtest.PathImages} {
+		if got := srv.Headers(path)[0].Get("User-Agent"); got != "custom" {
+			t.Fatalf("%s: expected the custom User-Agent, got %q", path, got)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+)
+
+func TestQueryBuild(t *testing.T) {
+	q := ddg_search.NewQuery("golang generics").
+		Phrase("type parameters").
+		Exclude("java").
+		Exclude("old tutorial").
+		Or("pdf", "slides", "talk video").
+		InTitle("release notes").
+		InURL("blog").
+		Site("go.dev").
+		ExcludeSite("example.com").
+		FileType(".PDF").
+		Bang("!g")
+
+	got, err := q.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `!g golang generics "type parameters" pdf OR slides OR "talk video" intitle:"release notes" ` +
+		`inurl:blog -java -"old tutorial" site:go.dev -site:example.com filetype:pdf`
+	if got != want {
+		t.Fatalf("unexpected query:\n got %s\nwant %s", got, want)
+	}
+	if q.String() != want {
+		t.Fatalf("expected String to match Build, got %s", q.String())
+	}
+}
+
+func TestQueryInvalid(t *testing.T) {
+	for name, q := range map[string]*ddg_search.Query{
+		"empty":              ddg_search.NewQuery(),
+		"empty phrase":       ddg_search.NewQuery("go").Phrase(" "),
+		"quote in phrase":    ddg_search.NewQuery("go").Phrase(`say "hi"`),
+		"site with space":    ddg_search.NewQuery("go").Site("go dev"),
+		"two sites":          ddg_search.NewQuery("go").Site("go.dev").Site("golang.org"),
+		"site excluded":      ddg_search.NewQuery("go").Site("go.dev").ExcludeSite("GO.DEV"),
+		"two filetypes":      ddg_search.NewQuery("go").FileType("pdf").FileType("doc"),
+		"invalid filetype":   ddg_search.NewQuery("go").FileType("p/df"),
+		"single OR":          ddg_search.NewQuery("go").Or("pdf"),
+		"two bangs":          ddg_search.NewQuery("go").Bang("w").Bang("gh"),
+		"inurl with spaces":  ddg_search.NewQuery("go").InURL("a b"),
+		"site in terms":      ddg_search.NewQuery("golang site:a.com filetype:pdf").Site("b.com").FileType("doc"),
+		"filetype in terms":  ddg_search.NewQuery("go", "FILETYPE:pdf"),
+		"OR in terms":        ddg_search.NewQuery("a", "OR", "b"),
+		"exclusion in terms": ddg_search.NewQuery("-java"),
+		"bang in terms":      ddg_search.NewQuery("go !w"),
+	} {
+		if _, err := q.Build(); !errors.Is(err, ddg_search.ErrInvalidParams) {
+			t.Errorf("%s: expected ErrInvalidParams, got %v", name, err)
+		}
+	}
+
+	// Lowercase or, lone dashes and unknown colons are plain keywords
+	if got, err := ddg_search.NewQuery("this or that - c++: x").Build(); err != nil || got != "this or that - c++: x" {
+		t.Fatalf("unexpected query %q, %v", got, err)
+	}
+
+	// Repeating the same site, filetype or bang is not an error
+	q := ddg_search.NewQuery("go").Site("go.dev").Site("go.dev").FileType("pdf").FileType(".pdf").Bang("w").Bang("!w")
+	if got, err := q.Build(); err != nil || got != "!w go site:go.dev filetype:pdf" {
+		t.Fatalf("unexpected query %q, %v", got, err)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := ddg_search.ParseQuery(`golang  "type parameters" -java -"old tutorial" ` +
+		`pdf OR "talk video" OR slides SITE:go.dev -site:example.com filetype:PDF intitle:"release notes" inurl:blog !g`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ddg_search.NewQuery("golang").
+		Phrase("type parameters").
+		Exclude("java").
+		Exclude("old tutorial").
+		Or("pdf", "talk video", "slides").
+		Site("go.dev").
+		ExcludeSite("example.com").
+		FileType("pdf").
+		InTitle("release notes").
+		InURL("blog").
+		Bang("g")
+	if !reflect.DeepEqual(q, want) {
+		t.Fatalf("unexpected query:\n got %s\nwant %s", q, want)
+	}
+}
+
+func TestParseQueryRoundTrip(t *testing.T) {
+	queries := []*ddg_search.Query{
+		ddg_search.NewQuery("golang"),
+		ddg_search.NewQuery("a", "b").Phrase("c d").Exclude("e f").Exclude("g"),
+		ddg_search.NewQuery().Or("x", "y z").Or("site:odd", "-odd", "OR").Site("example.com"),
+		ddg_search.NewQuery().InTitle("a:b").InTitle("c").InURL("d").FileType("txt").Bang("gh"),
+		ddg_search.NewQuery("x").Exclude("-x").Exclude("inurl:y").ExcludeSite("a.com").ExcludeSite("b.com"),
+	}
+	for _, q := range queries {
+		s, err := q.Build()
+		if err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+		parsed, err := ddg_search.ParseQuery(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if !reflect.DeepEqual(parsed, q) {
+			t.Fatalf("round trip of %s returned %s", s, parsed)
+		}
+	}
+}
+
+func TestParseQueryInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"   ",
+		`golang "unterminated`,
+		`"phrase"suffix`,
+		"OR golang",
+		"golang OR",
+		"a OR -b",
+		"a OR OR b",
+		"site:go.dev site:golang.org",
+		"go filetype:pdf filetype:doc",
+		"go site:go.dev -site:go.dev",
+		`go cache:"x y"`,
+		"go site:",
+	} {
+		if _, err := ddg_search.ParseQuery(s); !errors.Is(err, ddg_search.ErrInvalidParams) {
+			t.Errorf("%q: expected ErrInvalidParams, got %v", s, err)
+		}
+	}
+}
+
+func TestQueryWithSearch(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	keywords, err := ddg_search.NewQuery("golang").Site("go.dev").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := ddgs.Text(keywords, "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendHTML, 5)
+	if err != nil || len(results) == 0 {
+		t.Fatalf("expected results for a built query, got %v, %v", results, err)
+	}
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Patrick7241/ddg_search"
+)
+
+// recordingLimiter records the endpoints it is asked about
+type recordingLimiter struct {
+	mu        sync.Mutex
+	endpoints []ddg_search.Endpoint
+}
+
+func (l *recordingLimiter) Wait(ctx context.Context, endpoint ddg_search.Endpoint) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.endpoints = append(l.endpoints, endpoint)
+	return nil
+}
+
+func TestCustomRateLimiter(t *testing.T) {
+	limiter := &recordingLimiter{}
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithRateLimiter(limiter))
+
+	if _, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10); err != nil {
+		t.Fatal(err)
+	}
+	want := []ddg_search.Endpoint{ddg_search.EndpointVQD, ddg_search.EndpointImages, ddg_search.EndpointImages}
+	if !reflect.DeepEqual(limiter.endpoints, want) {
+		t.Fatalf("unexpected endpoints %v, want %v", limiter.endpoints, want)
+	}
+}
+
+func TestTokenBucketBurst(t *testing.T) {
+	limiter := ddg_search.NewTokenBucket(100*time.Millisecond, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx, ddg_search.EndpointHTML); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("burst requests should not wait, took %v", elapsed)
+	}
+	if err := limiter.Wait(ctx, ddg_search.EndpointHTML); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("third request should wait for a token, took %v", elapsed)
+	}
+}
+
+func TestTokenBucketPerEndpoint(t *testing.T) {
+	limiter := ddg_search.NewTokenBucket(time.Hour, 1).
+		SetEndpoint(ddg_search.EndpointLite, 0, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The default bucket is exhausted after one request
+	if err := limiter.Wait(ctx, ddg_search.EndpointHTML); err != nil {
+		t.Fatal(err)
+	}
+	if err := limiter.Wait(ctx, ddg_search.EndpointNews); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	// The lite endpoint has its own, unthrottled bucket
+	for i := 0; i < 10; i++ {
+		if err := limiter.Wait(context.Background(), ddg_search.EndpointLite); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	limiter := ddg_search.NewTokenBucket(time.Hour, 3)
+	first, _ := newFakeDDGS(t, ddg_search.WithRateLimiter(limiter))
+	second, _ := newFakeDDGS(t, ddg_search.WithRateLimiter(limiter))
+
+	// VQD lookup and the first news.js page use two of the three tokens
+	if _, err := first.News("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := second.NewsContext(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the shared budget to be exhausted, got %v", err)
+	}
+}
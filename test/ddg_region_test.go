@@ -0,0 +1,91 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+)
+
+func TestRegions(t *testing.T) {
+	regions := ddg_search.Regions()
+	if len(regions) < 60 || regions[0] != ddg_search.RegionWorldwide {
+		t.Fatalf("expected the catalogue to start with wt-wt, got %d regions starting with %v", len(regions), regions[0])
+	}
+	seen := make(map[ddg_search.Region]bool)
+	for _, r := range regions {
+		if seen[r] {
+			t.Fatalf("duplicate region %q", r)
+		}
+		seen[r] = true
+		if !r.Valid() || r.Name() == "" {
+			t.Fatalf("region %q has no name", r)
+		}
+	}
+
+	if name := ddg_search.Region("de-de").Name(); name != "Germany" {
+		t.Fatalf("unexpected name of de-de: %q", name)
+	}
+	if locale := ddg_search.Region("uk-en").Locale(); locale != "en-GB" {
+		t.Fatalf("unexpected locale of uk-en: %q", locale)
+	}
+	if r := ddg_search.Region("xx-xx"); r.Valid() || r.Name() != "" {
+		t.Fatal("expected xx-xx to be unknown")
+	}
+}
+
+func TestRegionFromLocale(t *testing.T) {
+	for locale, want := range map[string]ddg_search.Region{
+		"de-DE":      "de-de",
+		"en_GB":      "uk-en",
+		"en-US":      "us-en",
+		"ja-JP":      "jp-jp",
+		"zh-Hant-TW": "tw-tzh",
+		"zh-Hans":    "cn-zh",
+		"es-419":     "xl-es",
+		"nn-NO":      "no-no",
+		"FR-fr":      "fr-fr",
+		"wt-wt":      ddg_search.RegionWorldwide,
+	} {
+		got, err := ddg_search.RegionFromLocale(locale)
+		if err != nil || got != want {
+			t.Errorf("RegionFromLocale(%q) = %q, %v, want %q", locale, got, err, want)
+		}
+	}
+
+	for _, locale := range []string{"", "xx-YY", "de"} {
+		if _, err := ddg_search.RegionFromLocale(locale); !errors.Is(err, ddg_search.ErrInvalidParams) {
+			t.Errorf("RegionFromLocale(%q): expected ErrInvalidParams, got %v", locale, err)
+		}
+	}
+}
+
+func TestRegionValidation(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	if _, err := ddgs.Text("golang", "de-DE", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, ddg_search.BackendAuto, 10); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected a locale to be rejected as a region, got %v", err)
+	}
+	if _, err := ddgs.Images("golang", "xx-xx", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams from Images, got %v", err)
+	}
+	if _, err := ddgs.News("golang", "germany", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams from News, got %v", err)
+	}
+	if _, err := ddgs.Videos("golang", "xx-xx", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll,
+		ddg_search.ResolutionAll, ddg_search.DurationAll, ddg_search.LicenseAll, 0); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams from Videos, got %v", err)
+	}
+	if _, err := ddgs.Suggestions("golang", "xx-xx"); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams from Suggestions, got %v", err)
+	}
+	if srv.TotalRequests() != 0 {
+		t.Fatalf("expected invalid regions not to reach the server, got %d requests", srv.TotalRequests())
+	}
+
+	// The positional methods keep taking plain strings
+	var region string = "de-de"
+	if _, err := ddgs.News("golang", region, ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+var fastRetries = ddg_search.RetryPolicy{
+	MaxAttempts:        3,
+	BaseDelay:          time.Millisecond,
+	MaxDelay:           10 * time.Millisecond,
+	Jitter:             0.5,
+	RetryOnTimeout:     true,
+	RetryOnRatelimit:   true,
+	RetryOnServerError: true,
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithRetryPolicy(fastRetries))
+	srv.FailNext(ddgtest.PathNews, 2, http.StatusServiceUnavailable, "")
+
+	results, err := ddgs.News("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if n := srv.Requests(ddgtest.PathNews); n != 4 {
+		t.Fatalf("expected 2 failed and 2 successful news.js requests, got %d", n)
+	}
+}
+
+func TestRetryVQDRatelimit(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithRetryPolicy(fastRetries))
+	srv.FailNext(ddgtest.PathVQD, 1, http.StatusTooManyRequests, "")
+
+	if _, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 2 {
+		t.Fatalf("expected 2 VQD requests, got %d", n)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithRetryPolicy(fastRetries))
+	srv.SetStatus(ddgtest.PathHTML, http.StatusTooManyRequests)
+
+	_, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendHTML, 10)
+	if !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+	if n := srv.Requests(ddgtest.PathHTML); n != fastRetries.MaxAttempts {
+		t.Fatalf("expected %d attempts, got %d", fastRetries.MaxAttempts, n)
+	}
+}
+
+func TestRetryPerErrorClass(t *testing.T) {
+	policy := fastRetries
+	policy.RetryOnRatelimit = false
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithRetryPolicy(policy))
+	srv.FailNext(ddgtest.PathLite, 1, http.StatusForbidden, "")
+
+	_, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendLite, 10)
+	if !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+	if n := srv.Requests(ddgtest.PathLite); n != 1 {
+		t.Fatalf("expected a single attempt, got %d", n)
+	}
+
+	// Without a policy nothing is retried
+	ddgs, srv = newFakeDDGS(t)
+	srv.FailNext(ddgtest.PathVideos, 1, http.StatusBadGateway, "")
+	_, err = ddgs.Videos("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll,
+		ddg_search.ResolutionAll, ddg_search.DurationAll, ddg_search.LicenseAll, 10)
+	var statusErr *ddg_search.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway || !errors.Is(err, ddg_search.ErrSearch) {
+		t.Fatalf("expected 502 StatusError, got %v", err)
+	}
+}
+
+func TestRetryReusedVQDForbidden(t *testing.T) {
+	// A 403 on a cached token is answered by fetching a fresh token, not by
+	// backing off and retrying with the stale one
+	policy := fastRetries
+	policy.BaseDelay, policy.MaxDelay = time.Minute, time.Minute
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithRetryPolicy(policy))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := ddgs.ImagesContext(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); err != nil {
+		t.Fatal(err)
+	}
+	srv.FailNext(ddgtest.PathImages, 1, http.StatusForbidden, "")
+	if _, err := ddgs.ImagesContext(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 2 {
+		t.Fatalf("expected the token to be fetched again, got %d VQD requests", n)
+	}
+	if n := srv.Requests(ddgtest.PathImages); n != 3 {
+		t.Fatalf("expected one rejected and two successful i.js requests, got %d", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	policy := fastRetries
+	policy.RespectRetryAfter = true
+	policy.MaxDelay = 5 * time.Second
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithRetryPolicy(policy))
+	srv.FailNext(ddgtest.PathImages, 1, http.StatusTooManyRequests, "1")
+
+	start := time.Now()
+	if _, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("Retry-After was not respected, retried after %v", elapsed)
+	}
+}
+
+func TestRetryBackoffHonorsContext(t *testing.T) {
+	policy := fastRetries
+	policy.BaseDelay = time.Minute
+	policy.MaxDelay = time.Minute
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithRetryPolicy(policy))
+	srv.SetStatus(ddgtest.PathVQD, http.StatusTooManyRequests)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := ddgs.NewsContext(ctx, "golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 10)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestTextSeq(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	var results []ddg_search.TextResult
+	for r, err := range ddgs.TextSeq(context.Background(), "golang", "wt-wt",
+		ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, ddg_search.BackendHTML) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+
+	want := []ddg_search.TextResult{textGoDev, textWikipedia, textPkgGoDev, textGitHub}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("unexpected results:\n got %+v\nwant %+v", results, want)
+	}
+	if n := srv.Requests(ddgtest.PathHTML); n != 2 {
+		t.Fatalf("expected 2 html requests, got %d", n)
+	}
+}
+
+func TestSeqBreakStopsFetching(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	seq := ddgs.TextSeq(context.Background(), "golang", "wt-wt",
+		ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, ddg_search.BackendLite)
+	if n := srv.TotalRequests(); n != 0 {
+		t.Fatalf("expected no requests before ranging, got %d", n)
+	}
+
+	for r, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r != textGoDev {
+			t.Fatalf("unexpected first result %+v", r)
+		}
+		break
+	}
+	if n := srv.Requests(ddgtest.PathLite); n != 1 {
+		t.Fatalf("expected 1 lite request, got %d", n)
+	}
+}
+
+func TestSeqHasNoPageLimit(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	count := 0
+	for r, err := range ddgs.NewsSeq(context.Background(), ddgtest.QueryManyPages, "wt-wt",
+		ddg_search.SafeSearchModerate, ddg_search.TimelimitAll) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.URL == "" {
+			t.Fatalf("unexpected empty result %+v", r)
+		}
+		count++
+	}
+	if count != ddgtest.ManyPagesCount {
+		t.Fatalf("expected %d results, got %d", ddgtest.ManyPagesCount, count)
+	}
+	if n := srv.Requests(ddgtest.PathNews); n != ddgtest.ManyPagesCount {
+		t.Fatalf("expected %d news.js requests, got %d", ddgtest.ManyPagesCount, n)
+	}
+	// The VQD token is fetched once for the whole sequence
+	if n := srv.Requests(ddgtest.PathVQD); n != 1 {
+		t.Fatalf("expected 1 VQD request, got %d", n)
+	}
+
+	// The buffered variant keeps its page cap
+	results, err := ddgs.News(ddgtest.QueryManyPages, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+}
+
+func TestSeqYieldsErrors(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	var errs []error
+	for _, err := range ddgs.ImagesSeq(context.Background(), ddgtest.QueryRatelimit, "wt-wt",
+		ddg_search.SafeSearchModerate, ddg_search.TimelimitAll) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ddg_search.ErrRatelimit) {
+		t.Fatalf("expected a single ErrRatelimit, got %v", errs)
+	}
+
+	errs = nil
+	for _, err := range ddgs.VideosSeq(context.Background(), "", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.ResolutionAll, ddg_search.DurationAll, ddg_search.LicenseAll) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected a single ErrInvalidParams, got %v", errs)
+	}
+}
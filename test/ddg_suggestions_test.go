@@ -0,0 +1,88 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestSuggestions(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	suggestions, err := ddgs.Suggestions("golang", "de-de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ddg_search.Suggestion{
+		{Phrase: "golang"},
+		{Phrase: "golang tutorial"},
+		{Phrase: "golang generics"},
+		{Phrase: "golang vs rust"},
+		{Phrase: "golang playground"},
+	}
+	if !reflect.DeepEqual(suggestions, want) {
+		t.Fatalf("unexpected suggestions:\n got %+v\nwant %+v", suggestions, want)
+	}
+
+	h := srv.Headers(ddgtest.PathSuggestions)[0]
+	if h.Get("Sec-Fetch-Mode") != "cors" {
+		t.Fatalf("expected a fetch request, got %v", h)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 0 {
+		t.Fatalf("expected no VQD request, got %d", n)
+	}
+}
+
+func TestSuggestionsRegion(t *testing.T) {
+	var regions []string
+	srv := ddgtest.NewServer()
+	defer srv.Close()
+	recorder := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			regions = append(regions, req.URL.Query().Get("kl"))
+			return next.RoundTrip(req)
+		})
+	}
+	ddgs := ddg_search.NewDDGS(ddg_search.WithBaseURLs(srv.BaseURLs()),
+		ddg_search.WithSleepDuration(0), ddg_search.WithRoundTripperMiddleware(recorder))
+
+	for _, region := range []string{"fr-fr", ""} {
+		if _, err := ddgs.Suggestions("golang", region); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(regions, []string{"fr-fr", "wt-wt"}) {
+		t.Fatalf("expected the region to default to wt-wt, got %v", regions)
+	}
+}
+
+func TestSuggestionsEmpty(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	suggestions, err := ddgs.Suggestions(ddgtest.QueryNoResults, "wt-wt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions, got %+v", suggestions)
+	}
+}
+
+func TestSuggestionsErrors(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	if _, err := ddgs.Suggestions("", "wt-wt"); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	if _, err := ddgs.Suggestions(ddgtest.QueryMalformed, "wt-wt"); !errors.Is(err, ddg_search.ErrSearch) {
+		t.Fatalf("expected ErrSearch, got %v", err)
+	}
+	srv.SetStatus(ddgtest.PathSuggestions, http.StatusTooManyRequests)
+	if _, err := ddgs.Suggestions("golang", "wt-wt"); !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+}
@@ -1,35 +1,144 @@
 package test
 
 import (
-	"github.com/Patrick7241/ddg_search"
+	"errors"
+	"reflect"
 	"testing"
-	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
 )
 
-func TestTextDDG(t *testing.T) {
-	//创建带代理的客户端
-	ddgs := ddg_search.NewDDGS(
-		ddg_search.WithProxy("127.0.0.1:7890"), // add proxy
-		ddg_search.WithTimeout(10*time.Second),
-		ddg_search.WithSleepDuration(10*time.Second),
-	)
-
-	// 文本搜索
-	results, err := ddgs.Text(
-		"golang",
-		"wt-wt",
-		ddg_search.SafeSearchModerate,
-		ddg_search.TimelimitAll,
-		ddg_search.BackendAuto,
-		2,
-	)
+var (
+	textGoDev = ddg_search.TextResult{
+		Title: "The Go Programming Language",
+		Href:  "https://go.dev/",
+		Body:  "Go is an open source programming language that makes it simple to build secure, scalable systems.",
+	}
+	textWikipedia = ddg_search.TextResult{
+		Title: "Go (programming language) - Wikipedia",
+		Href:  "https://en.wikipedia.org/wiki/Go_(programming_language)",
+		Body:  "Go is a high-level general purpose programming language that is statically typed and compiled.",
+	}
+	textPkgGoDev = ddg_search.TextResult{
+		Title: "Go Packages",
+		Href:  "https://pkg.go.dev/",
+		Body:  "Go is an open source programming language. Discover packages and modules.",
+	}
+	textGitHub = ddg_search.TextResult{
+		Title: "GitHub - golang/go: The Go programming language",
+		Href:  "https://github.com/golang/go",
+		Body:  "The Go programming language. Contribute to golang/go development on GitHub.",
+	}
+)
+
+func TestTextHTML(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	results, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendHTML, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ddg_search.TextResult{textGoDev, textWikipedia, textPkgGoDev, textGitHub}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("unexpected results:\n got %+v\nwant %+v", results, want)
+	}
+	if n := srv.Requests(ddgtest.PathHTML); n != 2 {
+		t.Fatalf("expected 2 html requests, got %d", n)
+	}
+}
+
+func TestTextLite(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	results, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendLite, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ddg_search.TextResult{textGoDev, textWikipedia, textGitHub}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("unexpected results:\n got %+v\nwant %+v", results, want)
+	}
+	if n := srv.Requests(ddgtest.PathLite); n != 2 {
+		t.Fatalf("expected 2 lite requests, got %d", n)
+	}
+}
+
+func TestTextMaxResults(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	results, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendHTML, 1)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+
+	want := []ddg_search.TextResult{textGoDev}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("unexpected results:\n got %+v\nwant %+v", results, want)
+	}
+	if n := srv.Requests(ddgtest.PathHTML); n != 1 {
+		t.Fatalf("expected 1 html request, got %d", n)
+	}
+}
+
+func TestTextNoResults(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	for _, backend := range []ddg_search.Backend{ddg_search.BackendHTML, ddg_search.BackendLite} {
+		results, err := ddgs.Text(ddgtest.QueryNoResults, "wt-wt", ddg_search.SafeSearchModerate,
+			ddg_search.TimelimitAll, backend, 10)
+		if err != nil {
+			t.Fatalf("%s: %v", backend, err)
+		}
+		if len(results) != 0 {
+			t.Fatalf("%s: expected no results, got %+v", backend, results)
+		}
 	}
+}
+
+func TestTextRatelimit(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	for _, backend := range []ddg_search.Backend{ddg_search.BackendHTML, ddg_search.BackendLite, ddg_search.BackendAuto} {
+		_, err := ddgs.Text(ddgtest.QueryRatelimit, "wt-wt", ddg_search.SafeSearchModerate,
+			ddg_search.TimelimitAll, backend, 10)
+		if !errors.Is(err, ddg_search.ErrRatelimit) {
+			t.Fatalf("%s: expected ErrRatelimit, got %v", backend, err)
+		}
+	}
+}
+
+func TestTextAutoFallback(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+	srv.SetStatus(ddgtest.PathHTML, 202)
 
-	i := 1
-	for _, r := range results {
-		t.Logf("Title: %s number:%d \n", r["title"], i)
-		i++
+	for i := 0; i < 4; i++ {
+		results, err := ddgs.Text("golang", "wt-wt", ddg_search.SafeSearchModerate,
+			ddg_search.TimelimitAll, ddg_search.BackendAuto, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []ddg_search.TextResult{textGoDev, textWikipedia, textGitHub}
+		if !reflect.DeepEqual(results, want) {
+			t.Fatalf("unexpected results:\n got %+v\nwant %+v", results, want)
+		}
+	}
+}
+
+func TestTextInvalidParams(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	_, err := ddgs.Text("", "wt-wt", ddg_search.SafeSearchModerate,
+		ddg_search.TimelimitAll, ddg_search.BackendHTML, 10)
+	if !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	if n := srv.TotalRequests(); n != 0 {
+		t.Fatalf("expected no requests, got %d", n)
 	}
 }
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestTimeRange(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC)
+	tr := ddg_search.TimeRange(from, to)
+	if tr != "2024-01-01..2024-03-31" {
+		t.Fatalf("unexpected time range %q", tr)
+	}
+	gotFrom, gotTo, ok := tr.Range()
+	if !ok || !gotFrom.Equal(from) || gotTo.Format("2006-01-02") != "2024-03-31" {
+		t.Fatalf("unexpected range of %q: %v, %v, %v", tr, gotFrom, gotTo, ok)
+	}
+	for _, tl := range []ddg_search.Timelimit{ddg_search.TimelimitWeek, ddg_search.TimelimitAll, "2024-01-01..soon"} {
+		if _, _, ok := tl.Range(); ok {
+			t.Fatalf("expected %q not to be a range", tl)
+		}
+	}
+}
+
+func TestTimeRangeEncoding(t *testing.T) {
+	var forms []url.Values
+	recorder := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case ddgtest.PathNews:
+				forms = append(forms, req.URL.Query())
+			case ddgtest.PathHTML:
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				form, err := url.ParseQuery(string(body))
+				if err != nil {
+					return nil, err
+				}
+				forms = append(forms, form)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithRoundTripperMiddleware(recorder))
+	ctx := context.Background()
+	tr := ddg_search.TimeRange(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	if _, _, err := ddgs.TextPageWithOptions(ctx, "golang", ddg_search.TextOptions{Timelimit: tr, Backend: ddg_search.BackendHTML}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ddgs.NewsPageWithOptions(ctx, "golang", ddg_search.NewsOptions{Timelimit: tr}); err != nil {
+		t.Fatal(err)
+	}
+	if len(forms) != 2 {
+		t.Fatalf("expected a text and a news request, got %d", len(forms))
+	}
+	for _, form := range forms {
+		if df := form.Get("df"); df != "2024-01-01..2024-03-31" {
+			t.Fatalf("expected the range in df, got %q", df)
+		}
+	}
+}
+
+func TestTimeRangeErrors(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+	tr := ddg_search.TimeRange(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	if _, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, tr, 0); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected Images to reject a time range, got %v", err)
+	}
+	if _, err := ddgs.Videos("golang", "wt-wt", ddg_search.SafeSearchModerate, tr,
+		ddg_search.ResolutionAll, ddg_search.DurationAll, ddg_search.LicenseAll, 0); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected Videos to reject a time range, got %v", err)
+	}
+
+	for tl, detail := range map[ddg_search.Timelimit]string{
+		"2024-03-31..2024-01-01": "ends before it starts",
+		"2024-01-01..":           "malformed time range",
+		"2024-1-1..2024-3-31":    "malformed time range",
+	} {
+		_, err := ddgs.News("golang", "wt-wt", ddg_search.SafeSearchModerate, tl, 0)
+		if !errors.Is(err, ddg_search.ErrInvalidParams) || !strings.Contains(err.Error(), detail) {
+			t.Fatalf("%q: expected ErrInvalidParams mentioning %q, got %v", tl, detail, err)
+		}
+	}
+	if srv.TotalRequests() != 0 {
+		t.Fatalf("expected invalid time ranges not to reach the server, got %d requests", srv.TotalRequests())
+	}
+}
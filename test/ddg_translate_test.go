@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func TestTranslateDetectsLanguage(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	got, err := ddgs.Translate("Hallo Welt", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ddg_search.Translation{
+		Original:         "Hallo Welt",
+		Translated:       "[en] Hallo Welt",
+		DetectedLanguage: "de",
+		From:             "de",
+		To:               "en",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected translation:\n got %+v\nwant %+v", got, want)
+	}
+
+	headers := srv.Headers(ddgtest.PathTranslate)
+	if len(headers) != 1 || headers[0].Get("Content-Type") != "text/plain; charset=UTF-8" {
+		t.Fatalf("expected the text to be posted as plain text, got %v", headers)
+	}
+}
+
+func TestTranslateFromLanguage(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	for _, text := range []string{"Bonjour", "Merci"} {
+		got, err := ddgs.Translate(text, "fr", "de")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Translated != "[de] "+text || got.DetectedLanguage != "" || got.From != "fr" || got.To != "de" {
+			t.Fatalf("unexpected translation %+v", got)
+		}
+	}
+	// Every translation shares the VQD token of the translate query
+	if n := srv.Requests(ddgtest.PathVQD); n != 1 {
+		t.Fatalf("expected one VQD request, got %d", n)
+	}
+}
+
+func TestTranslateRefetchesOnForbidden(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	if _, err := ddgs.Translate("Hallo", "", "en"); err != nil {
+		t.Fatal(err)
+	}
+	srv.FailNext(ddgtest.PathTranslate, 1, http.StatusForbidden, "")
+	if _, err := ddgs.Translate("Hallo", "", "en"); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 2 {
+		t.Fatalf("expected the stale token to be refetched once, got %d VQD requests", n)
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	limiter := &recordingLimiter{}
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithRateLimiter(limiter))
+
+	if _, err := ddgs.Translate("", "", "en"); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	if srv.TotalRequests() != 0 {
+		t.Fatalf("expected no request for empty text, got %d", srv.TotalRequests())
+	}
+	if _, err := ddgs.Translate(ddgtest.QueryMalformed, "", "en"); !errors.Is(err, ddg_search.ErrSearch) {
+		t.Fatalf("expected ErrSearch for a malformed response, got %v", err)
+	}
+	if _, err := ddgs.Translate(ddgtest.QueryRatelimit, "", "en"); !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+	want := []ddg_search.Endpoint{ddg_search.EndpointVQD, ddg_search.EndpointTranslate, ddg_search.EndpointTranslate}
+	if !reflect.DeepEqual(limiter.endpoints, want) {
+		t.Fatalf("expected the limiter to see %v, got %v", want, limiter.endpoints)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ddgs.TranslateContext(ctx, "Hallo", "", "en"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
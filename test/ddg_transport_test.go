@@ -0,0 +1,155 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+// countingTransport counts the requests it forwards to the default transport
+type countingTransport struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.requests++
+	c.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (c *countingTransport) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	transport := &countingTransport{}
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithTransport(transport))
+	if err := searchImages(t, ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if transport.count() != 2 {
+		t.Fatalf("expected 2 requests through the transport, got %d", transport.count())
+	}
+}
+
+func TestWithTransportAndProxy(t *testing.T) {
+	proxy := ddgtest.NewHTTPProxy("", "")
+	defer proxy.Close()
+
+	transport := &http.Transport{MaxConnsPerHost: 1}
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithTransport(transport),
+		ddg_search.WithProxy(proxy.URL), ddg_search.WithNoProxy(""))
+	if err := searchImages(t, ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if proxy.Requests() != 2 {
+		t.Fatalf("expected 2 proxied requests, got %d", proxy.Requests())
+	}
+	if transport.Proxy != nil {
+		t.Fatal("expected the caller's transport not to be modified")
+	}
+}
+
+func TestWithTransportProxyRequiresHTTPTransport(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithTransport(&countingTransport{}),
+		ddg_search.WithProxy("127.0.0.1:8080"))
+	if err := searchImages(t, ddgs); !errors.Is(err, ddg_search.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	if srv.TotalRequests() != 0 {
+		t.Fatalf("expected no requests, got %d", srv.TotalRequests())
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	transport := &countingTransport{}
+	client := &http.Client{Transport: transport}
+	ddgs, _ := newFakeDDGS(t, ddg_search.WithHTTPClient(client))
+	if err := searchImages(t, ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if transport.count() != 2 {
+		t.Fatalf("expected 2 requests through the client transport, got %d", transport.count())
+	}
+	if client.Jar != nil || client.CheckRedirect != nil {
+		t.Fatal("expected the caller's client not to be modified")
+	}
+}
+
+func TestWithHTTPClientDoesNotFollowRedirects(t *testing.T) {
+	redirect := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == ddgtest.PathImages {
+			return &http.Response{
+				StatusCode: http.StatusMovedPermanently,
+				Header:     http.Header{"Location": {"/elsewhere"}},
+				Body:       http.NoBody,
+				Request:    req,
+			}, nil
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithHTTPClient(&http.Client{Transport: redirect}))
+
+	err := searchImages(t, ddgs)
+	var statusErr *ddg_search.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusMovedPermanently {
+		t.Fatalf("expected a 301 StatusError, got %v", err)
+	}
+	if srv.Requests("/elsewhere") != 0 {
+		t.Fatal("expected the redirect not to be followed")
+	}
+}
+
+func TestWithRoundTripperMiddleware(t *testing.T) {
+	proxy := ddgtest.NewHTTPProxy("", "")
+	defer proxy.Close()
+
+	var mu sync.Mutex
+	var calls []string
+	middleware := func(name string) func(http.RoundTripper) http.RoundTripper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls = append(calls, name)
+				mu.Unlock()
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	ddgs, _ := newFakeDDGS(t,
+		ddg_search.WithRoundTripperMiddleware(middleware("outer")),
+		ddg_search.WithRoundTripperMiddleware(middleware("inner")),
+		ddg_search.WithProxy(proxy.URL), ddg_search.WithNoProxy(""))
+	if err := searchImages(t, ddgs); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	want := []string{"outer", "inner", "outer", "inner"}
+	if len(calls) != len(want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("expected calls %v, got %v", want, calls)
+		}
+	}
+	if proxy.Requests() != 2 {
+		t.Fatalf("expected the middleware to keep the proxy, got %d proxied requests", proxy.Requests())
+	}
+}
@@ -1,34 +1,103 @@
 package test
 
 import (
-	"github.com/Patrick7241/ddg_search"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
 )
 
 func TestVideosDDG(t *testing.T) {
-	ddgs := ddg_search.NewDDGS(
-		ddg_search.WithProxy("127.0.0.1:7890"), // add proxy
-		ddg_search.WithTimeout(10*time.Second),
-		ddg_search.WithSleepDuration(10*time.Second),
-	)
+	ddgs, srv := newFakeDDGS(t)
 
-	// 文本搜索
 	results, err := ddgs.Videos(
-		"how to learn golang",
+		"golang",
 		"wt-wt",
 		ddg_search.SafeSearchModerate,
 		ddg_search.TimelimitAll,
 		ddg_search.ResolutionAll,
 		ddg_search.DurationAll,
 		ddg_search.LicenseAll,
-		1,
+		10,
 	)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 
-	for _, r := range results {
-		t.Logf("videos: %v \n", r)
+	want := []ddg_search.VideoResult{
+		{
+			Content:     "https://www.youtube.com/watch?v=gopher1",
+			Title:       "Go in 5 minutes",
+			Description: "Learn Go in 5 minutes.",
+			Duration:    4*time.Minute + 5*time.Second,
+			EmbedHTML:   `<iframe width="1280" height="720" src="https://www.youtube.com/embed/gopher1?autoplay=1" frameborder="0" allowfullscreen></iframe>`,
+			EmbedURL:    "https://www.youtube.com/embed/gopher1?autoplay=1",
+			ImageToken:  "a1b2c3",
+			Images: ddg_search.VideoImages{
+				Large:  "https://tse1.mm.bing.net/th?id=OVP.large1",
+				Medium: "https://tse1.mm.bing.net/th?id=OVP.medium1",
+				Motion: "https://tse1.mm.bing.net/th?id=OM.motion1",
+				Small:  "https://tse1.mm.bing.net/th?id=OVP.small1",
+			},
+			Provider:  "Bing",
+			Published: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+			Publisher: "YouTube",
+			Uploader:  "Gopher Academy",
+			ViewCount: 12345,
+		},
+		{
+			Content:     "https://www.youtube.com/watch?v=gopher2",
+			Title:       "Go full course",
+			Description: "A full Go course.",
+			Duration:    time.Hour + 2*time.Minute + 3*time.Second,
+			EmbedURL:    "https://www.youtube.com/embed/gopher2?autoplay=1",
+			ImageToken:  "d4e5f6",
+			Images: ddg_search.VideoImages{
+				Large:  "https://tse2.mm.bing.net/th?id=OVP.large2",
+				Medium: "https://tse2.mm.bing.net/th?id=OVP.medium2",
+				Small:  "https://tse2.mm.bing.net/th?id=OVP.small2",
+			},
+			Provider:  "Bing",
+			Published: time.Date(2022, 11, 20, 8, 30, 0, 0, time.UTC),
+			Publisher: "YouTube",
+			Uploader:  "Go Tutorials",
+		},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d: %+v", len(want), len(results), results)
+	}
+	for i, r := range results {
+		if r.Raw["content"] != want[i].Content {
+			t.Fatalf("result %d: raw item not preserved", i)
+		}
+		r.Raw = nil
+		if !reflect.DeepEqual(r, want[i]) {
+			t.Fatalf("result %d:\n got %+v\nwant %+v", i, r, want[i])
+		}
+	}
+	if n := srv.Requests(ddgtest.PathVideos); n != 2 {
+		t.Fatalf("expected 2 v.js requests, got %d", n)
+	}
+}
+
+func TestVideosErrors(t *testing.T) {
+	ddgs, _ := newFakeDDGS(t)
+
+	search := func(keywords string) ([]ddg_search.VideoResult, error) {
+		return ddgs.Videos(keywords, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll,
+			ddg_search.ResolutionAll, ddg_search.DurationAll, ddg_search.LicenseAll, 10)
+	}
+
+	if _, err := search(ddgtest.QueryRatelimit); !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+	if _, err := search(ddgtest.QueryMalformed); !errors.Is(err, ddg_search.ErrSearch) {
+		t.Fatalf("expected ErrSearch, got %v", err)
+	}
+	if results, err := search(ddgtest.QueryNoResults); err != nil || len(results) != 0 {
+		t.Fatalf("expected no results, got %+v, %v", results, err)
 	}
 }
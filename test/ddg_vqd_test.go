@@ -0,0 +1,112 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+func searchImagesFor(t *testing.T, ddgs *ddg_search.DDGS, keywords string) {
+	t.Helper()
+	_, err := ddgs.Images(keywords, "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0)
+	if err != nil {
+		t.Fatalf("search %q failed: %v", keywords, err)
+	}
+}
+
+func TestVQDCacheReusesToken(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	searchImagesFor(t, ddgs, "golang")
+	searchImagesFor(t, ddgs, "golang")
+	if _, err := ddgs.News("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0); err != nil {
+		t.Fatalf("news search failed: %v", err)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 1 {
+		t.Fatalf("expected one VQD request for the same query, got %d", n)
+	}
+
+	searchImagesFor(t, ddgs, "rust")
+	if n := srv.Requests(ddgtest.PathVQD); n != 2 {
+		t.Fatalf("expected a VQD request for a new query, got %d", n)
+	}
+}
+
+func TestVQDCacheExpires(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithVQDCache(20*time.Millisecond, 10))
+
+	searchImagesFor(t, ddgs, "golang")
+	time.Sleep(50 * time.Millisecond)
+	searchImagesFor(t, ddgs, "golang")
+	if n := srv.Requests(ddgtest.PathVQD); n != 2 {
+		t.Fatalf("expected the expired token to be refetched, got %d VQD requests", n)
+	}
+}
+
+func TestVQDCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithVQDCache(time.Hour, 2))
+
+	for _, q := range []string{"a", "b", "a", "c", "a", "b"} {
+		searchImagesFor(t, ddgs, q)
+	}
+	// a, b, c miss; a is kept as most recently used and b is evicted by c
+	if n := srv.Requests(ddgtest.PathVQD); n != 4 {
+		t.Fatalf("expected 4 VQD requests, got %d", n)
+	}
+}
+
+func TestVQDCacheDisabled(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t, ddg_search.WithVQDCache(0, 0))
+
+	searchImagesFor(t, ddgs, "golang")
+	searchImagesFor(t, ddgs, "golang")
+	if n := srv.Requests(ddgtest.PathVQD); n != 2 {
+		t.Fatalf("expected a VQD request per search, got %d", n)
+	}
+}
+
+func TestVQDCacheRefetchesOnForbidden(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	searchImagesFor(t, ddgs, "golang")
+	srv.FailNext(ddgtest.PathImages, 1, http.StatusForbidden, "")
+	searchImagesFor(t, ddgs, "golang")
+
+	if n := srv.Requests(ddgtest.PathVQD); n != 2 {
+		t.Fatalf("expected the stale token to be refetched once, got %d VQD requests", n)
+	}
+	if n := srv.Requests(ddgtest.PathImages); n != 3 {
+		t.Fatalf("expected the page to be requested again, got %d images requests", n)
+	}
+}
+
+func TestVQDCacheRefetchesOnlyOnce(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	searchImagesFor(t, ddgs, "golang")
+	srv.FailNext(ddgtest.PathImages, 2, http.StatusForbidden, "")
+	_, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0)
+	if !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 2 {
+		t.Fatalf("expected a single refetch, got %d VQD requests", n)
+	}
+}
+
+func TestVQDFreshTokenForbiddenIsNotRefetched(t *testing.T) {
+	ddgs, srv := newFakeDDGS(t)
+
+	srv.FailNext(ddgtest.PathImages, 1, http.StatusForbidden, "")
+	_, err := ddgs.Images("golang", "wt-wt", ddg_search.SafeSearchModerate, ddg_search.TimelimitAll, 0)
+	if !errors.Is(err, ddg_search.ErrRatelimit) {
+		t.Fatalf("expected ErrRatelimit, got %v", err)
+	}
+	if n := srv.Requests(ddgtest.PathVQD); n != 1 {
+		t.Fatalf("expected no refetch of a fresh token, got %d VQD requests", n)
+	}
+}
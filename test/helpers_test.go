@@ -0,0 +1,21 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Patrick7241/ddg_search"
+	"github.com/Patrick7241/ddg_search/ddgtest"
+)
+
+// newFakeDDGS returns a client wired to a fresh fake DuckDuckGo server
+func newFakeDDGS(t *testing.T, options ...func(*ddg_search.DDGS)) (*ddg_search.DDGS, *ddgtest.Server) {
+	t.Helper()
+	srv := ddgtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	options = append([]func(*ddg_search.DDGS){
+		ddg_search.WithBaseURLs(srv.BaseURLs()),
+		ddg_search.WithSleepDuration(0),
+	}, options...)
+	return ddg_search.NewDDGS(options...), srv
+}
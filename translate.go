@@ -0,0 +1,106 @@
+package ddg_search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// translateKeywords is the query the VQD token of translation.js is
+// fetched for
+const translateKeywords = "translate"
+
+// Translation is the result of Translate
+type Translation struct {
+	Original   string `json:"original"`
+	Translated string `json:"translated"`
+	// DetectedLanguage is the source language detected by DuckDuckGo. It
+	// is empty when the source language was given.
+	DetectedLanguage string `json:"detected_language"`
+	From             string `json:"from"`
+	To               string `json:"to"`
+}
+
+// Translate translates text from one language to another using language
+// codes such as "de" or "zh-Hans". An empty from detects the source
+// language, an empty to translates to English.
+func (d *DDGS) Translate(text, from, to string) (*Translation, error) {
+	return d.TranslateContext(context.Background(), text, from, to)
+}
+
+// TranslateContext is like Translate but uses ctx for the VQD lookup, the
+// rate limit wait and the translation request
+func (d *DDGS) TranslateContext(ctx context.Context, text, from, to string) (*Translation, error) {
+	if text == "" {
+		return nil, fmt.Errorf("%w: text is mandatory", ErrInvalidParams)
+	}
+	if to == "" {
+		to = "en"
+	}
+
+	params := url.Values{}
+	params.Set("query", translateKeywords)
+	params.Set("to", to)
+	if from != "" {
+		params.Set("from", from)
+	}
+
+	// Like jsonPage, a cached token answered with 403 is fetched again once
+	profile := d.profile(d.pickProfile())
+	vqd, cached, err := d.vqd(ctx, translateKeywords, profile)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("vqd", vqd)
+	body, err := d.postTranslation(ctx, params, text, profile, cached)
+	if cached && isForbidden(err) {
+		d.invalidateVQD(translateKeywords, profile)
+		vqd, _, err = d.vqd(ctx, translateKeywords, profile)
+		if err != nil {
+			return nil, err
+		}
+		params.Set("vqd", vqd)
+		body, err = d.postTranslation(ctx, params, text, profile, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		DetectedLanguage *string `json:"detected_language"`
+		Translated       string  `json:"translated"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("%w: json unmarshal error: %v", ErrSearch, err)
+	}
+	translation := &Translation{
+		Original:   text,
+		Translated: resp.Translated,
+		From:       from,
+		To:         to,
+	}
+	if resp.DetectedLanguage != nil {
+		translation.DetectedLanguage = *resp.DetectedLanguage
+	}
+	if translation.From == "" {
+		translation.From = translation.DetectedLanguage
+	}
+	return translation, nil
+}
+
+// postTranslation sends text to translation.js. reusedVQD is set when the
+// token in params came from the cache.
+func (d *DDGS) postTranslation(ctx context.Context, params url.Values, text string, profile BrowserProfile, reusedVQD bool) ([]byte, error) {
+	return d.doRequest(ctx, request{
+		method:    http.MethodPost,
+		endpoint:  EndpointTranslate,
+		params:    params,
+		body:      []byte(text),
+		headers:   jsonHeaders,
+		profile:   profile,
+		mode:      modeCORS,
+		reusedVQD: reusedVQD,
+	})
+}
@@ -0,0 +1,105 @@
+package ddg_search
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// WithHTTPClient makes the DDGS client send its requests with a copy of
+// client, keeping its transport, timeout and cookie jar. A nil Jar or
+// CheckRedirect is replaced with the defaults of NewDDGS: a fresh cookie jar
+// and a policy that does not follow redirects, which DuckDuckGo uses to
+// signal rate limits.
+func WithHTTPClient(client *http.Client) func(*DDGS) {
+	return func(d *DDGS) {
+		d.httpClient = client
+	}
+}
+
+// WithTransport sets the base RoundTripper of the DDGS client, replacing the
+// transport of WithHTTPClient. WithProxy and WithProxyPool require an
+// *http.Transport, which is cloned before the proxy is set.
+func WithTransport(transport http.RoundTripper) func(*DDGS) {
+	return func(d *DDGS) {
+		d.transport = transport
+	}
+}
+
+// WithRoundTripperMiddleware wraps the transport of the DDGS client, e.g. to
+// add logging or metrics. The first middleware is the outermost one and sees
+// every request first. Middleware runs after the proxy has been configured
+// and must keep the request context, which carries the proxy pool choice.
+func WithRoundTripperMiddleware(middleware ...func(http.RoundTripper) http.RoundTripper) func(*DDGS) {
+	return func(d *DDGS) {
+		d.middleware = append(d.middleware, middleware...)
+	}
+}
+
+// newClient builds the http.Client of d from its client, transport, proxy
+// and middleware options
+func (d *DDGS) newClient() (*http.Client, error) {
+	client := &http.Client{}
+	if d.httpClient != nil {
+		copied := *d.httpClient
+		client = &copied
+	}
+	if client.Jar == nil {
+		jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+		client.Jar = jar
+	}
+	if client.CheckRedirect == nil {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	transport := client.Transport
+	if d.transport != nil {
+		transport = d.transport
+	}
+
+	if d.proxy == "" && d.proxyPool == nil {
+		d.proxy = os.Getenv("DDGS_PROXY")
+	}
+	var proxyURL *url.URL
+	if d.proxy != "" && d.proxyPool == nil {
+		var err error
+		if proxyURL, err = parseProxy(d.proxy); err != nil {
+			return nil, err
+		}
+	}
+	if proxyURL != nil || d.proxyPool != nil {
+		noProxy := noProxyEnv()
+		if d.noProxy != nil {
+			noProxy = *d.noProxy
+		}
+		d.bypass = parseNoProxy(noProxy)
+
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		base, ok := transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("%w: proxy requires an *http.Transport, got %T", ErrInvalidParams, transport)
+		}
+		base = base.Clone()
+		base.Proxy = proxyFunc(proxyURL, d.bypass)
+		transport = base
+	}
+
+	if len(d.middleware) > 0 {
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		for i := len(d.middleware) - 1; i >= 0; i-- {
+			transport = d.middleware[i](transport)
+		}
+	}
+	client.Transport = transport
+	return client, nil
+}
@@ -0,0 +1,83 @@
+package ddg_search
+
+import (
+	"context"
+	"time"
+)
+
+// WithVQDCache configures the cache of VQD tokens used by the images, news
+// and videos searches. Tokens are reused for ttl and at most size queries
+// are kept, evicting the least recently used one. A ttl or size <= 0
+// disables the cache. The default is a 10 minute TTL and 128 queries.
+func WithVQDCache(ttl time.Duration, size int) func(*DDGS) {
+	return func(d *DDGS) {
+		d.vqdCache = newVQDCache(ttl, size)
+	}
+}
+
+// vqdCache is an LRU cache of VQD tokens keyed by browser profile and
+// query, so a token is only reused with the headers it was fetched with. A
+// nil *vqdCache caches nothing.
+type vqdCache struct {
+	tokens *lru[vqdKey, string]
+}
+
+// vqdKey identifies a cached token: the name of the profile it was fetched
+// with and its query
+type vqdKey struct {
+	profile  string
+	keywords string
+}
+
+func newVQDCache(ttl time.Duration, size int) *vqdCache {
+	if ttl <= 0 || size <= 0 {
+		return nil
+	}
+	return &vqdCache{tokens: newLRU[vqdKey, string](size, ttl)}
+}
+
+// get returns the cached token of key, if it has not expired
+func (c *vqdCache) get(key vqdKey) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	return c.tokens.get(key)
+}
+
+// put stores the token of key, evicting the least recently used entry when
+// the cache is full
+func (c *vqdCache) put(key vqdKey, vqd string) {
+	if c == nil {
+		return
+	}
+	c.tokens.put(key, vqd)
+}
+
+// invalidate drops the token of key
+func (c *vqdCache) invalidate(key vqdKey) {
+	if c == nil {
+		return
+	}
+	c.tokens.remove(key)
+}
+
+// vqd returns the VQD token of keywords for profile from the cache or, on a
+// miss, from DuckDuckGo. cached reports whether the token came from the
+// cache.
+func (d *DDGS) vqd(ctx context.Context, keywords string, profile BrowserProfile) (vqd string, cached bool, err error) {
+	key := vqdKey{profile: profile.Name, keywords: keywords}
+	if vqd, ok := d.vqdCache.get(key); ok {
+		return vqd, true, nil
+	}
+	vqd, err = d.getVQD(ctx, keywords, profile)
+	if err != nil {
+		return "", false, err
+	}
+	d.vqdCache.put(key, vqd)
+	return vqd, false, nil
+}
+
+// invalidateVQD drops the cached token of keywords for profile
+func (d *DDGS) invalidateVQD(keywords string, profile BrowserProfile) {
+	d.vqdCache.invalidate(vqdKey{profile: profile.Name, keywords: keywords})
+}